@@ -5,6 +5,7 @@ package main
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"log"
 	"os"
@@ -68,7 +69,7 @@ func main() {
 				safeString(cluster.ClusterType),
 				safeString(cluster.CloudProvider),
 				safeString(cluster.Region),
-				safeString(cluster.Status.ClusterStatus))
+				safeString((*string)(cluster.Status.ClusterStatus)))
 		}
 
 		// Example 3: List backups for the first cluster
@@ -101,8 +102,8 @@ func main() {
 				return
 			}
 
-			fmt.Printf("Found %d private endpoints:\n", len(endpoints.Items))
-			for _, endpoint := range endpoints.Items {
+			fmt.Printf("Found %d private endpoints:\n", len(endpoints.Endpoints))
+			for _, endpoint := range endpoints.Endpoints {
 				fmt.Printf("- ID: %s, Name: %s, Provider: %s, Status: %s\n",
 					safeString(endpoint.ID),
 					safeString(endpoint.EndpointName),
@@ -139,8 +140,9 @@ func main() {
 func handleError(operation string, err error) {
 	fmt.Printf("Error %s: %v\n", operation, err)
 
-	// Check if it's a TiDB Cloud API error
-	if apiErr, ok := err.(errors.APIError); ok {
+	// Check if it's a TiDB Cloud API error; errors are wrapped, so use errors.As
+	var apiErr errors.APIError
+	if stderrors.As(err, &apiErr) {
 		fmt.Printf("API Error Details:\n")
 		fmt.Printf("  Status Code: %d\n", apiErr.StatusCode)
 		fmt.Printf("  Error Code: %d\n", apiErr.Code)
@@ -164,8 +166,11 @@ func handleError(operation string, err error) {
 			fmt.Println("  Type: Unknown Error")
 		}
 
-		if len(apiErr.Details) > 0 {
-			fmt.Printf("  Details: %v\n", apiErr.Details)
+		if category := apiErr.Category(); category != errors.CategoryUnknown {
+			fmt.Printf("  Category: %s\n", category)
+		}
+		for _, detail := range apiErr.Details {
+			fmt.Printf("  Detail: %s\n", detail.Type)
 		}
 	}
 }
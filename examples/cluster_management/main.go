@@ -9,7 +9,7 @@ import (
 	"os"
 	"time"
 
-	"github.com/5st7/tidb-cloud-go/pkg/client"
+	tidbcloud "github.com/5st7/tidb-cloud-go/pkg/client"
 	"github.com/5st7/tidb-cloud-go/pkg/models"
 )
 
@@ -23,7 +23,7 @@ func main() {
 	}
 
 	// Create a new TiDB Cloud client
-	client, err := client.NewClient(publicKey, privateKey)
+	client, err := tidbcloud.NewClient(publicKey, privateKey)
 	if err != nil {
 		log.Fatalf("Failed to create client: %v", err)
 	}
@@ -75,32 +75,20 @@ func main() {
 	fmt.Printf("Cluster created successfully! ID: %s\n", clusterID)
 	fmt.Printf("Cluster status: %s\n", *cluster.Status.ClusterStatus)
 
-	// Example 2: Wait for cluster to be available (in a real scenario, you'd want to poll)
+	// Example 2: Wait for cluster to be available
 	fmt.Println("\n=== Monitoring Cluster Status ===")
-	for i := 0; i < 10; i++ {
-		time.Sleep(30 * time.Second)
-
-		clusterInfo, err := client.GetCluster(projectID, clusterID)
-		if err != nil {
-			log.Printf("Failed to get cluster info: %v", err)
-			continue
-		}
-
-		status := *clusterInfo.Status.ClusterStatus
-		fmt.Printf("Cluster status: %s\n", status)
-
-		if status == "AVAILABLE" {
-			fmt.Println("Cluster is now available!")
-			break
-		}
-
-		if status == "UNAVAILABLE" || status == "FAILED" {
-			fmt.Printf("Cluster creation failed with status: %s\n", status)
-			return
-		}
-
-		fmt.Printf("Waiting for cluster to be ready... (attempt %d/10)\n", i+1)
+	_, err = client.WaitForClusterStatus(context.Background(), projectID, clusterID, models.OpenapiClusterStatusAVAILABLE, &tidbcloud.WaitOptions{
+		Interval: 30 * time.Second,
+		Timeout:  time.Hour,
+		OnProgress: func(p tidbcloud.WaitProgress) {
+			fmt.Printf("Cluster status: %s (%v elapsed)\n", p.Status, p.Elapsed.Round(time.Second))
+		},
+	})
+	if err != nil {
+		fmt.Printf("Cluster did not become available: %v\n", err)
+		return
 	}
+	fmt.Println("Cluster is now available!")
 
 	// Example 3: Update cluster configuration
 	fmt.Println("\n=== Updating Cluster Configuration ===")
@@ -160,10 +148,10 @@ func main() {
 	ctx := context.Background()
 
 	// First, create the private endpoint service
-	service, err := client.CreatePrivateEndpointService(ctx, projectID, clusterID)
+	serviceResp, err := client.CreatePrivateEndpointService(ctx, projectID, clusterID)
 	if err != nil {
 		log.Printf("Failed to create private endpoint service: %v", err)
-	} else {
+	} else if service := serviceResp.PrivateEndpointService; service != nil {
 		fmt.Printf("Private endpoint service created. Status: %s\n", *service.Status)
 		fmt.Printf("Service name: %s\n", *service.Name)
 
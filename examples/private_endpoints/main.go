@@ -4,13 +4,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"time"
 
-	"github.com/5st7/tidb-cloud-go/pkg/client"
-	"github.com/5st7/tidb-cloud-go/pkg/models"
+	tidbcloud "github.com/5st7/tidb-cloud-go/pkg/client"
 )
 
 func main() {
@@ -23,7 +23,7 @@ func main() {
 	}
 
 	// Create a new TiDB Cloud client
-	client, err := client.NewClient(publicKey, privateKey)
+	client, err := tidbcloud.NewClient(publicKey, privateKey)
 	if err != nil {
 		log.Fatalf("Failed to create client: %v", err)
 	}
@@ -77,12 +77,12 @@ func main() {
 	if err != nil {
 		log.Printf("Failed to list project private endpoints: %v", err)
 	} else {
-		fmt.Printf("Found %d private endpoints in the project:\n", len(allEndpoints.Items))
-		for _, endpoint := range allEndpoints.Items {
+		fmt.Printf("Found %d private endpoints in the project:\n", len(allEndpoints.Endpoints))
+		for _, endpoint := range allEndpoints.Endpoints {
 			fmt.Printf("- ID: %s\n", safeString(endpoint.ID))
 			fmt.Printf("  Cluster: %s\n", safeString(endpoint.ClusterID))
 			fmt.Printf("  Provider: %s\n", safeString(endpoint.CloudProvider))
-			fmt.Printf("  Region: %s\n", safeString(endpoint.Region))
+			fmt.Printf("  Region: %s\n", safeString(endpoint.RegionName))
 			fmt.Printf("  Endpoint Name: %s\n", safeString(endpoint.EndpointName))
 			fmt.Printf("  Status: %s\n", safeString(endpoint.Status))
 			fmt.Printf("  Service Name: %s\n", safeString(endpoint.ServiceName))
@@ -93,14 +93,14 @@ func main() {
 	// Example 2: Check if private endpoint service exists for the cluster
 	fmt.Println("=== Checking Private Endpoint Service ===")
 
-	service, err := client.GetPrivateEndpointService(ctx, projectID, clusterID)
+	serviceResp, err := client.GetPrivateEndpointService(ctx, projectID, clusterID)
 	if err != nil {
 		fmt.Printf("No private endpoint service found, creating one...\n")
 
 		// Example 3: Create private endpoint service
 		fmt.Println("\n=== Creating Private Endpoint Service ===")
 
-		service, err = client.CreatePrivateEndpointService(ctx, projectID, clusterID)
+		serviceResp, err = client.CreatePrivateEndpointService(ctx, projectID, clusterID)
 		if err != nil {
 			log.Fatalf("Failed to create private endpoint service: %v", err)
 		}
@@ -110,6 +110,11 @@ func main() {
 		fmt.Println("Private endpoint service already exists!")
 	}
 
+	service := serviceResp.PrivateEndpointService
+	if service == nil {
+		log.Fatalf("No private endpoint service in the response")
+	}
+
 	// Display service information
 	fmt.Printf("Service Details:\n")
 	fmt.Printf("  Cloud Provider: %s\n", safeString(service.CloudProvider))
@@ -128,8 +133,8 @@ func main() {
 	if err != nil {
 		log.Printf("Failed to list private endpoints: %v", err)
 	} else {
-		fmt.Printf("Found %d private endpoints for this cluster:\n", len(endpoints.Items))
-		for _, endpoint := range endpoints.Items {
+		fmt.Printf("Found %d private endpoints for this cluster:\n", len(endpoints.Endpoints))
+		for _, endpoint := range endpoints.Endpoints {
 			fmt.Printf("- ID: %s\n", safeString(endpoint.ID))
 			fmt.Printf("  Endpoint Name: %s\n", safeString(endpoint.EndpointName))
 			fmt.Printf("  Status: %s\n", safeString(endpoint.Status))
@@ -158,7 +163,7 @@ func main() {
 		}
 	}
 	fmt.Println("3. Copy the VPC endpoint ID (e.g., vpce-xxxxxxxxx for AWS)")
-	fmt.Println("4. Use CreatePrivateEndpoint API with the endpoint ID")
+	fmt.Println("4. Use EnsurePrivateEndpoint with the endpoint ID")
 
 	// Example 6: Simulate creating a private endpoint (with placeholder endpoint ID)
 	// NOTE: In real usage, you would get this ID after creating the VPC endpoint in your cloud console
@@ -168,51 +173,33 @@ func main() {
 			fmt.Println("\nTo create a private endpoint, set VPC_ENDPOINT_ID environment variable")
 			fmt.Println("and run with 'create-endpoint' argument")
 		} else {
-			fmt.Printf("\n=== Creating Private Endpoint with ID: %s ===\n", endpointName)
-
-			createReq := &models.OpenapiCreatePrivateEndpointReq{
-				EndpointName: stringPtr(endpointName),
-			}
-
-			endpoint, err := client.CreatePrivateEndpoint(ctx, projectID, clusterID, createReq)
-			if err != nil {
-				log.Printf("Failed to create private endpoint: %v", err)
-			} else {
-				fmt.Printf("Private endpoint created successfully!\n")
-				fmt.Printf("  ID: %s\n", safeString(endpoint.ID))
-				fmt.Printf("  Status: %s\n", safeString(endpoint.Status))
-				fmt.Printf("  Message: %s\n", safeString(endpoint.Message))
-
-				// Monitor connection status
-				fmt.Println("\n=== Monitoring Private Endpoint Status ===")
-				for i := 0; i < 10; i++ {
-					time.Sleep(30 * time.Second)
-
-					endpoints, err := client.ListPrivateEndpoints(ctx, projectID, clusterID)
-					if err != nil {
-						log.Printf("Failed to check endpoint status: %v", err)
-						continue
-					}
-
-					for _, ep := range endpoints.Items {
-						if ep.ID != nil && *ep.ID == *endpoint.ID {
-							status := safeString(ep.Status)
-							fmt.Printf("Endpoint status: %s\n", status)
-
-							if status == "ACTIVE" {
-								fmt.Println("Private endpoint is now active!")
-								return
-							}
-
-							if status == "FAILED" {
-								fmt.Printf("Private endpoint failed: %s\n", safeString(ep.Message))
-								return
-							}
-						}
-					}
-
-					fmt.Printf("Waiting for endpoint to be active... (attempt %d/10)\n", i+1)
-				}
+			fmt.Printf("\n=== Provisioning Private Endpoint with ID: %s ===\n", endpointName)
+
+			// Provisioning reuses the service and endpoint if they exist, and
+			// waits until both are ACTIVE
+			provisionCtx, provisionCancel := context.WithTimeout(context.Background(), 30*time.Minute)
+			defer provisionCancel()
+
+			conn, err := client.EnsurePrivateEndpoint(provisionCtx, projectID, clusterID, &tidbcloud.PrivateEndpointSpec{
+				EndpointName: endpointName,
+				Wait: &tidbcloud.WaitOptions{
+					Interval: 30 * time.Second,
+					OnProgress: func(p tidbcloud.WaitProgress) {
+						fmt.Printf("%s status: %s\n", p.Resource, p.Status)
+					},
+				},
+			})
+			var failed *tidbcloud.PrivateEndpointFailedError
+			switch {
+			case errors.As(err, &failed):
+				fmt.Printf("Private endpoint failed: %s\n", failed.Message)
+			case err != nil:
+				log.Printf("Failed to provision private endpoint: %v", err)
+			default:
+				fmt.Println("Private endpoint is now active!")
+				fmt.Printf("  ID: %s\n", safeString(conn.Endpoint.ID))
+				fmt.Printf("  Connect to: %s:%d\n", conn.DNSName, conn.Port)
+				return
 			}
 		}
 	}
@@ -233,10 +220,6 @@ func main() {
 }
 
 // Helper functions
-func stringPtr(s string) *string {
-	return &s
-}
-
 func safeString(s *string) string {
 	if s == nil {
 		return ""
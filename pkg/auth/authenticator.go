@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Authenticator adds credentials to TiDB Cloud API requests.
+// Implementations must be safe for concurrent use.
+type Authenticator interface {
+	// Authenticate sets the credentials of req before it is sent.
+	// Implementations that sign the body read it through req.GetBody,
+	// which is set for every request that has a body.
+	Authenticate(req *http.Request) error
+
+	// HandleChallenge is called with the 401 response to req and reports
+	// whether the request should be authenticated again and resent.
+	HandleChallenge(req *http.Request, resp *http.Response) (bool, error)
+}
+
+// DigestAuthenticator authenticates requests with HTTP Digest Authentication
+// using a TiDB Cloud API key pair. After the first challenge the server nonce
+// is reused, so later requests are authenticated up front.
+//
+// The key pair is taken from a CredentialsProvider for every request, so keys
+// rotated by the provider are picked up without recreating the client; the
+// digest state is reset whenever the key changes.
+type DigestAuthenticator struct {
+	provider CredentialsProvider
+	digest   *DigestAuth
+
+	mu        sync.Mutex
+	publicKey string // Public key the current digest state was negotiated for
+}
+
+// NewDigestAuthenticator creates a digest authenticator for a fixed API key pair.
+func NewDigestAuthenticator(publicKey, privateKey string) (*DigestAuthenticator, error) {
+	creds := Credentials{PublicKey: publicKey, PrivateKey: privateKey, Source: "static"}
+	if err := creds.Validate(); err != nil {
+		return nil, err
+	}
+	return NewDigestAuthenticatorFromProvider(StaticProvider{Credentials: creds}), nil
+}
+
+// NewDigestAuthenticatorFromProvider creates a digest authenticator that
+// consults provider for every request. Wrap slow providers, such as a
+// credential_process command, in a CredentialsCache.
+func NewDigestAuthenticatorFromProvider(provider CredentialsProvider) *DigestAuthenticator {
+	return &DigestAuthenticator{
+		provider: provider,
+		digest:   NewDigestAuth(),
+	}
+}
+
+// Authenticate sets the Authorization header once a challenge is known, and
+// sends the request unauthenticated otherwise so the server issues one.
+func (a *DigestAuthenticator) Authenticate(req *http.Request) error {
+	req.Header.Del("Authorization")
+
+	creds, err := a.credentials(req)
+	if err != nil {
+		return err
+	}
+
+	// Sign from one snapshot, so a challenge parsed concurrently cannot change
+	// the parameters between deciding whether the body is needed and hashing it
+	p, ok := a.digest.next()
+	if !ok {
+		return nil
+	}
+
+	var body io.Reader
+	if p.qop == qopAuthInt && req.GetBody != nil {
+		b, err := req.GetBody()
+		if err != nil {
+			return fmt.Errorf("failed to rewind request body: %w", err)
+		}
+		defer b.Close()
+		body = b
+	}
+
+	header, err := p.authHeader(creds.PublicKey, creds.PrivateKey, req.Method, req.URL.RequestURI(), body)
+	if err != nil {
+		return fmt.Errorf("failed to generate auth header: %w", err)
+	}
+
+	req.Header.Set("Authorization", header)
+	return nil
+}
+
+// HandleChallenge parses the Digest challenge of a 401 response. The request is
+// resent if it was unauthenticated, signed with a nonce other than the one
+// challenged (e.g. an expired nonce, whether or not the server marks it stale),
+// or signed with a key that has since been rotated. Otherwise the credentials
+// were rejected under the current nonce: a CredentialsCache is invalidated so
+// the next request retrieves them again, and the 401 is returned.
+func (a *DigestAuthenticator) HandleChallenge(req *http.Request, resp *http.Response) (bool, error) {
+	authHeaders := resp.Header.Values("WWW-Authenticate")
+	if len(authHeaders) == 0 {
+		return false, nil
+	}
+
+	nonce, stale, err := a.digest.parseChallenges(authHeaders)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse auth challenge: %w", err)
+	}
+
+	sent := sentDigest(req.Header.Get("Authorization"))
+	sentWith := sent["username"]
+	if sentWith == "" || stale || sent["nonce"] != nonce {
+		return true, nil
+	}
+
+	if cache, ok := a.provider.(*CredentialsCache); ok {
+		cache.Invalidate()
+	}
+	creds, err := a.provider.Retrieve(req.Context())
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve credentials: %w", err)
+	}
+	return creds.PublicKey != sentWith, nil
+}
+
+// credentials retrieves the key pair for req and resets the digest state if
+// the public key differs from the one it was negotiated for.
+func (a *DigestAuthenticator) credentials(req *http.Request) (Credentials, error) {
+	creds, err := a.provider.Retrieve(req.Context())
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to retrieve credentials: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if creds.PublicKey != a.publicKey {
+		if a.publicKey != "" {
+			a.digest.Reset()
+		}
+		a.publicKey = creds.PublicKey
+	}
+	return creds, nil
+}
+
+// sentDigest returns the parameters of a Digest Authorization header, such as
+// its username and nonce, or nil if the header is not a Digest one.
+func sentDigest(header string) map[string]string {
+	if !strings.HasPrefix(header, "Digest ") {
+		return nil
+	}
+	return parseKeyValuePairs(strings.TrimPrefix(header, "Digest "))
+}
+
+// TokenSource supplies bearer tokens, e.g. from an OAuth flow.
+type TokenSource interface {
+	// Token returns a valid access token.
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is a TokenSource that always returns the same token.
+type StaticToken string
+
+// Token implements TokenSource.
+func (t StaticToken) Token(ctx context.Context) (string, error) {
+	if t == "" {
+		return "", errors.New("token is empty")
+	}
+	return string(t), nil
+}
+
+// BearerAuthenticator authenticates requests with an
+// "Authorization: Bearer <token>" header.
+type BearerAuthenticator struct {
+	source TokenSource
+}
+
+// NewBearerAuthenticator creates an authenticator that takes tokens from source
+// for every request.
+func NewBearerAuthenticator(source TokenSource) (*BearerAuthenticator, error) {
+	if source == nil {
+		return nil, errors.New("token source is required")
+	}
+	return &BearerAuthenticator{source: source}, nil
+}
+
+// Authenticate implements Authenticator.
+func (a *BearerAuthenticator) Authenticate(req *http.Request) error {
+	token, err := a.source.Token(req.Context())
+	if err != nil {
+		return fmt.Errorf("failed to get token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// HandleChallenge implements Authenticator. A rejected token is not retried.
+func (a *BearerAuthenticator) HandleChallenge(req *http.Request, resp *http.Response) (bool, error) {
+	return false, nil
+}
+
+// AuthenticatorFunc adapts a request signing function to the Authenticator
+// interface, e.g. for a gateway that expects its own headers.
+// A 401 response is not retried.
+type AuthenticatorFunc func(req *http.Request) error
+
+// Authenticate calls f(req).
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// HandleChallenge implements Authenticator.
+func (f AuthenticatorFunc) HandleChallenge(req *http.Request, resp *http.Response) (bool, error) {
+	return false, nil
+}
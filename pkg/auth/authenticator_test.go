@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewDigestAuthenticator(t *testing.T) {
+	tests := []struct {
+		name        string
+		publicKey   string
+		privateKey  string
+		expectedErr string
+	}{
+		{name: "valid keys", publicKey: "public", privateKey: "private"},
+		{name: "empty public key", privateKey: "private", expectedErr: "public key is required"},
+		{name: "empty private key", publicKey: "public", expectedErr: "private key is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewDigestAuthenticator(tt.publicKey, tt.privateKey)
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("NewDigestAuthenticator() error = %v, want %q", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil || a == nil {
+				t.Errorf("NewDigestAuthenticator() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDigestAuthenticator(t *testing.T) {
+	a, err := NewDigestAuthenticator("public", "private")
+	if err != nil {
+		t.Fatalf("NewDigestAuthenticator() unexpected error: %v", err)
+	}
+
+	newRequest := func() *http.Request {
+		body := []byte(`{"name":"test"}`)
+		req, _ := http.NewRequest("POST", "https://api.tidbcloud.com/api/v1beta/projects?page=2", bytes.NewReader(body))
+		return req
+	}
+	challenge := func(header string) *http.Response {
+		resp := &http.Response{StatusCode: http.StatusUnauthorized, Header: http.Header{}}
+		resp.Header.Set("WWW-Authenticate", header)
+		return resp
+	}
+
+	// Without a challenge the request is sent unauthenticated
+	req := newRequest()
+	req.Header.Set("Authorization", "leftover")
+	if err := a.Authenticate(req); err != nil {
+		t.Fatalf("Authenticate() unexpected error: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "" {
+		t.Errorf("Authenticate() before challenge set Authorization = %q", got)
+	}
+
+	retry, err := a.HandleChallenge(req, challenge(`Digest realm="tidbcloud", nonce="n1", qop="auth-int"`))
+	if err != nil || !retry {
+		t.Fatalf("HandleChallenge() on unauthenticated request = %v, %v, want true", retry, err)
+	}
+
+	// auth-int reads the body through GetBody
+	if err := a.Authenticate(req); err != nil {
+		t.Fatalf("Authenticate() unexpected error: %v", err)
+	}
+	authHeader := req.Header.Get("Authorization")
+	if !strings.Contains(authHeader, `uri="/api/v1beta/projects?page=2"`) || !strings.Contains(authHeader, "qop=auth-int") {
+		t.Errorf("Authenticate() Authorization = %s", authHeader)
+	}
+	if body, _ := io.ReadAll(req.Body); string(body) != `{"name":"test"}` {
+		t.Errorf("Authenticate() consumed the request body, got %q", body)
+	}
+
+	// A request signed with another nonce is resent, whether or not the
+	// server marks the nonce stale
+	retry, err = a.HandleChallenge(req, challenge(`Digest realm="tidbcloud", nonce="n2", qop="auth"`))
+	if err != nil || !retry {
+		t.Errorf("HandleChallenge() on new nonce = %v, %v, want true", retry, err)
+	}
+	retry, err = a.HandleChallenge(req, challenge(`Digest realm="tidbcloud", nonce="n3", qop="auth", stale=true`))
+	if err != nil || !retry {
+		t.Errorf("HandleChallenge() on stale nonce = %v, %v, want true", retry, err)
+	}
+
+	// A request rejected under the nonce it was signed with is not resent
+	if err := a.Authenticate(req); err != nil {
+		t.Fatalf("Authenticate() unexpected error: %v", err)
+	}
+	retry, err = a.HandleChallenge(req, challenge(`Digest realm="tidbcloud", nonce="n3", qop="auth"`))
+	if err != nil || retry {
+		t.Errorf("HandleChallenge() on rejected credentials = %v, %v, want false", retry, err)
+	}
+
+	if _, err := a.HandleChallenge(req, challenge(`Basic realm="tidbcloud"`)); err == nil {
+		t.Error("HandleChallenge() expected error for non-digest challenge")
+	}
+}
+
+type tokenSourceFunc func(ctx context.Context) (string, error)
+
+func (f tokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+func TestBearerAuthenticator(t *testing.T) {
+	tests := []struct {
+		name           string
+		source         TokenSource
+		expectedHeader string
+		expectedErr    bool
+	}{
+		{name: "static token", source: StaticToken("abc123"), expectedHeader: "Bearer abc123"},
+		{name: "empty static token", source: StaticToken(""), expectedErr: true},
+		{
+			name: "token source error",
+			source: tokenSourceFunc(func(ctx context.Context) (string, error) {
+				return "", errors.New("token expired")
+			}),
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewBearerAuthenticator(tt.source)
+			if err != nil {
+				t.Fatalf("NewBearerAuthenticator() unexpected error: %v", err)
+			}
+
+			req, _ := http.NewRequest("GET", "https://api.tidbcloud.com/api/v1beta/projects", nil)
+			err = a.Authenticate(req)
+			if tt.expectedErr {
+				if err == nil {
+					t.Error("Authenticate() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() unexpected error: %v", err)
+			}
+			if got := req.Header.Get("Authorization"); got != tt.expectedHeader {
+				t.Errorf("Authorization = %q, want %q", got, tt.expectedHeader)
+			}
+			if retry, _ := a.HandleChallenge(req, &http.Response{StatusCode: http.StatusUnauthorized}); retry {
+				t.Error("HandleChallenge() = true, want false")
+			}
+		})
+	}
+
+	if _, err := NewBearerAuthenticator(nil); err == nil {
+		t.Error("NewBearerAuthenticator(nil) expected error but got none")
+	}
+}
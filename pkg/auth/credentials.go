@@ -0,0 +1,462 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Environment variables read by EnvProvider and the default provider chain.
+const (
+	EnvPublicKey       = "TIDBCLOUD_PUBLIC_KEY"
+	EnvPrivateKey      = "TIDBCLOUD_PRIVATE_KEY"
+	EnvProfile         = "TIDBCLOUD_PROFILE"
+	EnvCredentialsFile = "TIDBCLOUD_CREDENTIALS_FILE"
+
+	// DefaultProfile is the profile used when none is named
+	DefaultProfile = "default"
+
+	// DefaultCredentialsCacheTTL is how long a CredentialsCache keeps
+	// credentials that carry no expiry of their own
+	DefaultCredentialsCacheTTL = 5 * time.Minute
+)
+
+// legacyEnvPublicKey and legacyEnvPrivateKey are the variable names used in
+// earlier examples; they are read when the TIDBCLOUD_ variables are unset.
+const (
+	legacyEnvPublicKey  = "TIDB_CLOUD_PUBLIC_KEY"
+	legacyEnvPrivateKey = "TIDB_CLOUD_PRIVATE_KEY"
+)
+
+// ErrNoCredentials is returned by a CredentialsProvider that has no credentials
+// to offer, so that a ChainProvider moves on to the next provider.
+var ErrNoCredentials = errors.New("no credentials found")
+
+// Credentials is a TiDB Cloud API key pair.
+type Credentials struct {
+	PublicKey  string
+	PrivateKey string
+	// Source names the provider the credentials came from
+	Source string
+	// Expires is when the credentials must be retrieved again; zero means unknown
+	Expires time.Time
+}
+
+// Validate checks that both keys are set.
+func (c Credentials) Validate() error {
+	if c.PublicKey == "" {
+		return errors.New("public key is required")
+	}
+	if c.PrivateKey == "" {
+		return errors.New("private key is required")
+	}
+	return nil
+}
+
+// CredentialsProvider retrieves API credentials.
+type CredentialsProvider interface {
+	// Retrieve returns the credentials, or an error wrapping ErrNoCredentials
+	// if the provider is not configured.
+	Retrieve(ctx context.Context) (Credentials, error)
+}
+
+// StaticProvider returns fixed credentials.
+type StaticProvider struct {
+	Credentials Credentials
+}
+
+// Retrieve implements CredentialsProvider.
+func (p StaticProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	creds := p.Credentials
+	if creds.PublicKey == "" && creds.PrivateKey == "" {
+		return Credentials{}, fmt.Errorf("static: %w", ErrNoCredentials)
+	}
+	if err := creds.Validate(); err != nil {
+		return Credentials{}, fmt.Errorf("static: %w", err)
+	}
+	if creds.Source == "" {
+		creds.Source = "static"
+	}
+	return creds, nil
+}
+
+// EnvProvider reads credentials from TIDBCLOUD_PUBLIC_KEY and TIDBCLOUD_PRIVATE_KEY,
+// falling back to TIDB_CLOUD_PUBLIC_KEY and TIDB_CLOUD_PRIVATE_KEY.
+type EnvProvider struct{}
+
+// Retrieve implements CredentialsProvider.
+func (EnvProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	creds := Credentials{
+		PublicKey:  os.Getenv(EnvPublicKey),
+		PrivateKey: os.Getenv(EnvPrivateKey),
+		Source:     "environment",
+	}
+	if creds.PublicKey == "" && creds.PrivateKey == "" {
+		creds.PublicKey = os.Getenv(legacyEnvPublicKey)
+		creds.PrivateKey = os.Getenv(legacyEnvPrivateKey)
+	}
+
+	if creds.PublicKey == "" && creds.PrivateKey == "" {
+		return Credentials{}, fmt.Errorf("environment: %w", ErrNoCredentials)
+	}
+	if err := creds.Validate(); err != nil {
+		return Credentials{}, fmt.Errorf("environment: %w", err)
+	}
+	return creds, nil
+}
+
+// ProfileProvider reads a named profile from a credentials file in INI or
+// simple TOML form:
+//
+//	[default]
+//	public_key = "..."
+//	private_key = "..."
+//
+//	[ci]
+//	credential_process = "vault-tidbcloud-keys --org ci"
+//
+// A profile either holds the key pair or a credential_process command, which is
+// run as by ProcessProvider.
+type ProfileProvider struct {
+	// Path is the credentials file; empty uses DefaultCredentialsFile
+	Path string
+	// Profile is the profile name; empty uses TIDBCLOUD_PROFILE, then "default"
+	Profile string
+}
+
+// Retrieve implements CredentialsProvider. A missing file or profile yields
+// an error wrapping ErrNoCredentials.
+func (p ProfileProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	path := p.Path
+	if path == "" {
+		var err error
+		if path, err = DefaultCredentialsFile(); err != nil {
+			return Credentials{}, fmt.Errorf("profile: %w", ErrNoCredentials)
+		}
+	}
+
+	profile := p.Profile
+	if profile == "" {
+		profile = os.Getenv(EnvProfile)
+	}
+	if profile == "" {
+		profile = DefaultProfile
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Credentials{}, fmt.Errorf("profile %q: %s: %w", profile, path, ErrNoCredentials)
+		}
+		return Credentials{}, fmt.Errorf("profile %q: failed to read credentials file: %w", profile, err)
+	}
+
+	profiles, err := parseProfiles(data)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("profile %q: %s: %w", profile, path, err)
+	}
+
+	values, ok := profiles[profile]
+	if !ok {
+		return Credentials{}, fmt.Errorf("profile %q: not found in %s: %w", profile, path, ErrNoCredentials)
+	}
+
+	if command := values["credential_process"]; command != "" {
+		creds, err := ProcessProvider{Command: command}.Retrieve(ctx)
+		if err != nil {
+			return Credentials{}, fmt.Errorf("profile %q: %w", profile, err)
+		}
+		creds.Source = fmt.Sprintf("profile %s (credential_process)", profile)
+		return creds, nil
+	}
+
+	creds := Credentials{
+		PublicKey:  values["public_key"],
+		PrivateKey: values["private_key"],
+		Source:     "profile " + profile,
+	}
+	if err := creds.Validate(); err != nil {
+		return Credentials{}, fmt.Errorf("profile %q: %w", profile, err)
+	}
+	return creds, nil
+}
+
+// DefaultCredentialsFile returns TIDBCLOUD_CREDENTIALS_FILE if set, and
+// otherwise tidbcloud/credentials under the user config directory,
+// e.g. ~/.config/tidbcloud/credentials on Linux.
+func DefaultCredentialsFile() (string, error) {
+	if path := os.Getenv(EnvCredentialsFile); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tidbcloud", "credentials"), nil
+}
+
+// ProcessProvider runs an external command that prints the credentials as JSON:
+//
+//	{"public_key": "...", "private_key": "...", "expiration": "2025-01-02T15:04:05Z"}
+//
+// The optional expiration, in RFC 3339 format, sets Credentials.Expires.
+// The command is run by the system shell, so it may include arguments.
+type ProcessProvider struct {
+	Command string
+}
+
+// Retrieve implements CredentialsProvider.
+func (p ProcessProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	if p.Command == "" {
+		return Credentials{}, fmt.Errorf("credential_process: %w", ErrNoCredentials)
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd.exe", "/C", p.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "/bin/sh", "-c", p.Command)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return Credentials{}, fmt.Errorf("credential_process failed: %w: %s", err, msg)
+		}
+		return Credentials{}, fmt.Errorf("credential_process failed: %w", err)
+	}
+
+	var out struct {
+		PublicKey  string    `json:"public_key"`
+		PrivateKey string    `json:"private_key"`
+		Expiration time.Time `json:"expiration"`
+	}
+	if err := json.Unmarshal(output, &out); err != nil {
+		return Credentials{}, fmt.Errorf("credential_process: failed to decode output: %w", err)
+	}
+
+	creds := Credentials{
+		PublicKey:  out.PublicKey,
+		PrivateKey: out.PrivateKey,
+		Source:     "credential_process",
+		Expires:    out.Expiration,
+	}
+	if err := creds.Validate(); err != nil {
+		return Credentials{}, fmt.Errorf("credential_process: %w", err)
+	}
+	return creds, nil
+}
+
+// ChainProvider tries each provider in order and returns the first credentials
+// found. Providers reporting ErrNoCredentials are skipped; any other error
+// stops the chain, so a broken configuration is not silently bypassed.
+type ChainProvider struct {
+	Providers []CredentialsProvider
+}
+
+// NewChainProvider creates a chain of the given providers.
+func NewChainProvider(providers ...CredentialsProvider) *ChainProvider {
+	return &ChainProvider{Providers: providers}
+}
+
+// DefaultCredentialsChain looks up credentials in the environment, then in the
+// profile named by TIDBCLOUD_PROFILE (or "default") of the credentials file.
+func DefaultCredentialsChain() *ChainProvider {
+	return NewChainProvider(EnvProvider{}, ProfileProvider{})
+}
+
+// Retrieve implements CredentialsProvider.
+func (c *ChainProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	var missing []string
+	for _, provider := range c.Providers {
+		creds, err := provider.Retrieve(ctx)
+		if err == nil {
+			return creds, nil
+		}
+		if !errors.Is(err, ErrNoCredentials) {
+			return Credentials{}, err
+		}
+		missing = append(missing, err.Error())
+	}
+
+	if len(missing) == 0 {
+		return Credentials{}, ErrNoCredentials
+	}
+	return Credentials{}, fmt.Errorf("%w (tried: %s)", ErrNoCredentials, strings.Join(missing, "; "))
+}
+
+// CredentialsCache wraps a provider so that it can be consulted for every
+// request: credentials are reused until they expire, then retrieved again,
+// which picks up keys rotated by a secrets manager. Concurrent refreshes are
+// coalesced into one provider call, and while the cached credentials have not
+// expired they are still returned if a refresh fails. A CredentialsCache is
+// safe for concurrent use.
+type CredentialsCache struct {
+	provider CredentialsProvider
+	ttl      time.Duration
+
+	mu          sync.Mutex
+	creds       Credentials
+	expires     time.Time
+	invalidated time.Time
+	refresh     *credentialsRefresh
+}
+
+// credentialsRefresh is a provider call shared by the Retrieve calls waiting on it.
+type credentialsRefresh struct {
+	done  chan struct{}
+	creds Credentials
+	err   error
+}
+
+// minRefreshInterval bounds how often a CredentialsCache consults its provider
+// ahead of the TTL: Invalidate takes effect at most once per interval, and a
+// failed refresh is retried after it while the cached credentials are used.
+const minRefreshInterval = 30 * time.Second
+
+// NewCredentialsCache creates a cache in front of provider. Credentials
+// without an expiry are kept for ttl; a ttl of zero or less uses
+// DefaultCredentialsCacheTTL.
+func NewCredentialsCache(provider CredentialsProvider, ttl time.Duration) *CredentialsCache {
+	if ttl <= 0 {
+		ttl = DefaultCredentialsCacheTTL
+	}
+	return &CredentialsCache{provider: provider, ttl: ttl}
+}
+
+// Retrieve implements CredentialsProvider. The provider is called without
+// holding the cache lock, and a Retrieve whose context is done returns without
+// waiting for it.
+func (c *CredentialsCache) Retrieve(ctx context.Context) (Credentials, error) {
+	c.mu.Lock()
+	if time.Now().Before(c.expires) {
+		creds := c.creds
+		c.mu.Unlock()
+		return creds, nil
+	}
+	r := c.refresh
+	if r == nil {
+		r = &credentialsRefresh{done: make(chan struct{})}
+		c.refresh = r
+		go c.load(context.WithoutCancel(ctx), r)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return r.creds, r.err
+	case <-ctx.Done():
+		return Credentials{}, ctx.Err()
+	}
+}
+
+// load retrieves credentials from the provider for r and caches them. If the
+// provider fails, the cached credentials are used as long as they have not
+// expired, and the provider is consulted again after minRefreshInterval.
+func (c *CredentialsCache) load(ctx context.Context, r *credentialsRefresh) {
+	creds, err := c.provider.Retrieve(ctx)
+
+	c.mu.Lock()
+	now := time.Now()
+	if err == nil {
+		expires := now.Add(c.ttl)
+		if !creds.Expires.IsZero() && creds.Expires.Before(expires) {
+			expires = creds.Expires
+		}
+		c.creds = creds
+		c.expires = expires
+		r.creds = creds
+	} else if c.creds.PublicKey != "" && (c.creds.Expires.IsZero() || now.Before(c.creds.Expires)) {
+		expires := now.Add(minRefreshInterval)
+		if !c.creds.Expires.IsZero() && c.creds.Expires.Before(expires) {
+			expires = c.creds.Expires
+		}
+		c.expires = expires
+		r.creds = c.creds
+	} else {
+		r.err = err
+	}
+	if c.refresh == r {
+		c.refresh = nil
+	}
+	c.mu.Unlock()
+
+	close(r.done)
+}
+
+// Invalidate drops the cached credentials so the next Retrieve consults the
+// provider, e.g. after the server rejected them. It takes effect at most once
+// per minRefreshInterval, so a burst of rejected requests triggers a single
+// refresh.
+func (c *CredentialsCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if !c.invalidated.IsZero() && now.Sub(c.invalidated) < minRefreshInterval {
+		return
+	}
+	c.invalidated = now
+	c.expires = time.Time{}
+}
+
+// parseProfiles parses INI sections of key = value pairs. Values may be quoted
+// as in TOML; lines starting with # or ; are comments.
+func parseProfiles(data []byte) (map[string]map[string]string, error) {
+	profiles := make(map[string]map[string]string)
+	var current map[string]string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: invalid section header", lineNo)
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			name = strings.TrimPrefix(name, "profile ")
+			name = strings.Trim(strings.TrimSpace(name), `"`)
+			if profiles[name] == nil {
+				profiles[name] = make(map[string]string)
+			}
+			current = profiles[name]
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNo)
+		}
+		if current == nil {
+			return nil, fmt.Errorf("line %d: key outside of a profile section", lineNo)
+		}
+
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		current[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
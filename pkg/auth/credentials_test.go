@@ -0,0 +1,418 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testCredentialsFile = `# TiDB Cloud credentials
+[default]
+public_key = "default-public"
+private_key = "default-private"
+
+[profile staging]
+public_key = staging-public
+private_key = 'staging-private'
+
+[broken]
+public_key = only-public
+
+[ci]
+credential_process = echo '{"public_key": "ci-public", "private_key": "ci-private"}'
+
+[failing]
+credential_process = echo "vault is sealed" >&2; exit 3
+`
+
+func writeCredentialsFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write credentials file: %v", err)
+	}
+	return path
+}
+
+func clearCredentialsEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{EnvPublicKey, EnvPrivateKey, EnvProfile, EnvCredentialsFile, legacyEnvPublicKey, legacyEnvPrivateKey} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestEnvProvider(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           map[string]string
+		expected      Credentials
+		expectMissing bool
+		expectErr     bool
+	}{
+		{
+			name:     "TIDBCLOUD variables",
+			env:      map[string]string{EnvPublicKey: "pub", EnvPrivateKey: "priv"},
+			expected: Credentials{PublicKey: "pub", PrivateKey: "priv", Source: "environment"},
+		},
+		{
+			name:     "legacy variables",
+			env:      map[string]string{legacyEnvPublicKey: "old-pub", legacyEnvPrivateKey: "old-priv"},
+			expected: Credentials{PublicKey: "old-pub", PrivateKey: "old-priv", Source: "environment"},
+		},
+		{
+			name:          "unset",
+			env:           map[string]string{},
+			expectMissing: true,
+		},
+		{
+			name:      "private key missing",
+			env:       map[string]string{EnvPublicKey: "pub"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearCredentialsEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			creds, err := EnvProvider{}.Retrieve(context.Background())
+			switch {
+			case tt.expectMissing:
+				if !errors.Is(err, ErrNoCredentials) {
+					t.Errorf("Retrieve() error = %v, want ErrNoCredentials", err)
+				}
+			case tt.expectErr:
+				if err == nil || errors.Is(err, ErrNoCredentials) {
+					t.Errorf("Retrieve() error = %v, want validation error", err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("Retrieve() unexpected error: %v", err)
+				}
+				if creds != tt.expected {
+					t.Errorf("Retrieve() = %+v, want %+v", creds, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestProfileProvider(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential_process test commands use a POSIX shell")
+	}
+
+	clearCredentialsEnv(t)
+	path := writeCredentialsFile(t, testCredentialsFile)
+
+	tests := []struct {
+		name          string
+		provider      ProfileProvider
+		profileEnv    string
+		expected      Credentials
+		expectMissing bool
+		expectErr     bool
+	}{
+		{
+			name:     "default profile",
+			provider: ProfileProvider{Path: path},
+			expected: Credentials{PublicKey: "default-public", PrivateKey: "default-private", Source: "profile default"},
+		},
+		{
+			name:     "named profile with unquoted and single-quoted values",
+			provider: ProfileProvider{Path: path, Profile: "staging"},
+			expected: Credentials{PublicKey: "staging-public", PrivateKey: "staging-private", Source: "profile staging"},
+		},
+		{
+			name:       "profile from environment",
+			provider:   ProfileProvider{Path: path},
+			profileEnv: "staging",
+			expected:   Credentials{PublicKey: "staging-public", PrivateKey: "staging-private", Source: "profile staging"},
+		},
+		{
+			name:     "credential_process",
+			provider: ProfileProvider{Path: path, Profile: "ci"},
+			expected: Credentials{PublicKey: "ci-public", PrivateKey: "ci-private", Source: "profile ci (credential_process)"},
+		},
+		{
+			name:      "failing credential_process",
+			provider:  ProfileProvider{Path: path, Profile: "failing"},
+			expectErr: true,
+		},
+		{
+			name:      "incomplete profile",
+			provider:  ProfileProvider{Path: path, Profile: "broken"},
+			expectErr: true,
+		},
+		{
+			name:          "unknown profile",
+			provider:      ProfileProvider{Path: path, Profile: "prod"},
+			expectMissing: true,
+		},
+		{
+			name:          "missing file",
+			provider:      ProfileProvider{Path: filepath.Join(t.TempDir(), "nope")},
+			expectMissing: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvProfile, tt.profileEnv)
+
+			creds, err := tt.provider.Retrieve(context.Background())
+			switch {
+			case tt.expectMissing:
+				if !errors.Is(err, ErrNoCredentials) {
+					t.Errorf("Retrieve() error = %v, want ErrNoCredentials", err)
+				}
+			case tt.expectErr:
+				if err == nil || errors.Is(err, ErrNoCredentials) {
+					t.Errorf("Retrieve() error = %v, want a configuration error", err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("Retrieve() unexpected error: %v", err)
+				}
+				if creds != tt.expected {
+					t.Errorf("Retrieve() = %+v, want %+v", creds, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestParseProfiles_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "unterminated section", content: "[default\npublic_key = a\n"},
+		{name: "key outside section", content: "public_key = a\n"},
+		{name: "line without value", content: "[default]\npublic_key\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseProfiles([]byte(tt.content)); err == nil {
+				t.Error("parseProfiles() expected error but got none")
+			}
+		})
+	}
+}
+
+func TestChainProvider(t *testing.T) {
+	clearCredentialsEnv(t)
+	path := writeCredentialsFile(t, testCredentialsFile)
+	t.Setenv(EnvCredentialsFile, path)
+
+	// Environment takes precedence over the credentials file
+	t.Setenv(EnvPublicKey, "env-public")
+	t.Setenv(EnvPrivateKey, "env-private")
+	creds, err := DefaultCredentialsChain().Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("Retrieve() unexpected error: %v", err)
+	}
+	if creds.Source != "environment" {
+		t.Errorf("Retrieve() source = %s, want environment", creds.Source)
+	}
+
+	// Falls back to the default profile
+	t.Setenv(EnvPublicKey, "")
+	t.Setenv(EnvPrivateKey, "")
+	creds, err = DefaultCredentialsChain().Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("Retrieve() unexpected error: %v", err)
+	}
+	if creds.PublicKey != "default-public" {
+		t.Errorf("Retrieve() public key = %s, want default-public", creds.PublicKey)
+	}
+
+	// A broken provider stops the chain
+	chain := NewChainProvider(ProfileProvider{Path: path, Profile: "broken"}, StaticProvider{Credentials: Credentials{PublicKey: "a", PrivateKey: "b"}})
+	if _, err := chain.Retrieve(context.Background()); err == nil || errors.Is(err, ErrNoCredentials) {
+		t.Errorf("Retrieve() error = %v, want the broken profile error", err)
+	}
+
+	// Nothing configured
+	chain = NewChainProvider(EnvProvider{}, ProfileProvider{Path: path, Profile: "prod"})
+	if _, err := chain.Retrieve(context.Background()); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("Retrieve() error = %v, want ErrNoCredentials", err)
+	}
+}
+
+// rotatingProvider returns the credentials currently set, counting lookups.
+type rotatingProvider struct {
+	mu      sync.Mutex
+	creds   Credentials
+	lookups int
+}
+
+func (p *rotatingProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lookups++
+	return p.creds, nil
+}
+
+func (p *rotatingProvider) rotate(publicKey, privateKey string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.creds = Credentials{PublicKey: publicKey, PrivateKey: privateKey}
+}
+
+func TestCredentialsCache(t *testing.T) {
+	provider := &rotatingProvider{creds: Credentials{PublicKey: "pub-1", PrivateKey: "priv-1"}}
+	cache := NewCredentialsCache(provider, 50*time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		creds, err := cache.Retrieve(context.Background())
+		if err != nil {
+			t.Fatalf("Retrieve() unexpected error: %v", err)
+		}
+		if creds.PublicKey != "pub-1" {
+			t.Errorf("Retrieve() public key = %s, want pub-1", creds.PublicKey)
+		}
+	}
+	if provider.lookups != 1 {
+		t.Errorf("Expected 1 provider lookup while cached, got %d", provider.lookups)
+	}
+
+	// Rotation is picked up once the cached entry expires
+	provider.rotate("pub-2", "priv-2")
+	time.Sleep(60 * time.Millisecond)
+	if creds, _ := cache.Retrieve(context.Background()); creds.PublicKey != "pub-2" {
+		t.Errorf("Retrieve() after expiry public key = %s, want pub-2", creds.PublicKey)
+	}
+
+	// Invalidate forces a lookup, at most once per minRefreshInterval
+	provider.rotate("pub-3", "priv-3")
+	cache.Invalidate()
+	if creds, _ := cache.Retrieve(context.Background()); creds.PublicKey != "pub-3" {
+		t.Errorf("Retrieve() after Invalidate public key = %s, want pub-3", creds.PublicKey)
+	}
+	lookups := provider.lookups
+	cache.Invalidate()
+	cache.Retrieve(context.Background())
+	if provider.lookups != lookups {
+		t.Errorf("Expected repeated Invalidate to be ignored, got %d more lookups", provider.lookups-lookups)
+	}
+
+	// Credentials that expire before the TTL are refreshed at their expiry
+	expiring := &rotatingProvider{creds: Credentials{PublicKey: "a", PrivateKey: "b", Expires: time.Now().Add(-time.Second)}}
+	cache = NewCredentialsCache(expiring, time.Hour)
+	cache.Retrieve(context.Background())
+	cache.Retrieve(context.Background())
+	if expiring.lookups != 2 {
+		t.Errorf("Expected expired credentials to be retrieved again, got %d lookups", expiring.lookups)
+	}
+}
+
+// blockingProvider returns its credentials or error once released, counting lookups.
+type blockingProvider struct {
+	release chan struct{}
+	creds   Credentials
+	err     error
+	lookups atomic.Int32
+}
+
+func (p *blockingProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	p.lookups.Add(1)
+	<-p.release
+	return p.creds, p.err
+}
+
+func TestCredentialsCache_Refresh(t *testing.T) {
+	provider := &blockingProvider{release: make(chan struct{}), creds: Credentials{PublicKey: "pub-1", PrivateKey: "priv-1"}}
+	cache := NewCredentialsCache(provider, time.Hour)
+
+	// A caller whose context is done stops waiting for the provider
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := cache.Retrieve(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Retrieve() with expired context error = %v, want deadline exceeded", err)
+	}
+
+	// Concurrent callers share the refresh already in flight
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if creds, err := cache.Retrieve(context.Background()); err != nil || creds.PublicKey != "pub-1" {
+				t.Errorf("Retrieve() = %v, %v, want pub-1", creds, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(provider.release)
+	wg.Wait()
+	if got := provider.lookups.Load(); got != 1 {
+		t.Errorf("Expected 1 provider lookup, got %d", got)
+	}
+
+	// A failed refresh falls back to the cached credentials while they are valid
+	provider.err = errors.New("secrets manager unavailable")
+	cache.Invalidate()
+	if creds, err := cache.Retrieve(context.Background()); err != nil || creds.PublicKey != "pub-1" {
+		t.Errorf("Retrieve() after failed refresh = %v, %v, want cached pub-1", creds, err)
+	}
+	cache.Retrieve(context.Background())
+	if got := provider.lookups.Load(); got != 2 {
+		t.Errorf("Expected the failed refresh not to be retried immediately, got %d lookups", got)
+	}
+
+	// Without valid cached credentials the error is returned
+	cache = NewCredentialsCache(provider, time.Hour)
+	if _, err := cache.Retrieve(context.Background()); err == nil || err.Error() != "secrets manager unavailable" {
+		t.Errorf("Retrieve() without cached credentials error = %v", err)
+	}
+}
+
+func TestDigestAuthenticator_Rotation(t *testing.T) {
+	provider := &rotatingProvider{creds: Credentials{PublicKey: "pub-1", PrivateKey: "priv-1"}}
+	a := NewDigestAuthenticatorFromProvider(provider)
+
+	challenge := &http.Response{StatusCode: http.StatusUnauthorized, Header: http.Header{}}
+	challenge.Header.Set("WWW-Authenticate", `Digest realm="tidbcloud", nonce="n1", qop="auth"`)
+
+	req, _ := http.NewRequest("GET", "https://api.tidbcloud.com/api/v1beta/projects", nil)
+	a.Authenticate(req)
+	if retry, err := a.HandleChallenge(req, challenge); err != nil || !retry {
+		t.Fatalf("HandleChallenge() = %v, %v, want true", retry, err)
+	}
+	a.Authenticate(req)
+	if got := sentDigest(req.Header.Get("Authorization"))["username"]; got != "pub-1" {
+		t.Fatalf("Authorization username = %q, want pub-1", got)
+	}
+
+	// The old key is rejected after rotation: the request is resent with the new key
+	provider.rotate("pub-2", "priv-2")
+	if retry, err := a.HandleChallenge(req, challenge); err != nil || !retry {
+		t.Errorf("HandleChallenge() after rotation = %v, %v, want true", retry, err)
+	}
+
+	// The digest state negotiated for the old key is discarded
+	next, _ := http.NewRequest("GET", "https://api.tidbcloud.com/api/v1beta/projects", nil)
+	a.Authenticate(next)
+	if got := next.Header.Get("Authorization"); got != "" {
+		t.Errorf("Authorization after key change = %q, want unauthenticated request", got)
+	}
+
+	// Rejected credentials that have not changed are not retried
+	a.HandleChallenge(next, challenge)
+	a.Authenticate(next)
+	if retry, err := a.HandleChallenge(next, challenge); err != nil || retry {
+		t.Errorf("HandleChallenge() with unchanged key = %v, %v, want false", retry, err)
+	}
+}
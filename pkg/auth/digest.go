@@ -1,81 +1,194 @@
-// Package auth provides HTTP Digest Authentication implementation
-// for the TiDB Cloud SDK. It supports RFC 2617 compliant digest authentication
-// with MD5 hashing and quality of protection (qop) handling.
+// Package auth provides the authentication schemes of the TiDB Cloud SDK
+// behind the Authenticator interface. The default is HTTP Digest
+// Authentication, which supports RFC 7616 digest authentication with MD5,
+// SHA-256 and SHA-512-256 hashing, their -sess variants, and the auth and
+// auth-int qualities of protection (qop). Bearer tokens and custom request
+// signers are also supported.
 package auth
 
 import (
 	"crypto/md5"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
 	"errors"
 	"fmt"
+	"hash"
+	"io"
 	"regexp"
 	"strings"
+	"sync"
 )
 
-// DigestAuth implements HTTP Digest Authentication according to RFC 2617.
+// Supported digest algorithms, as advertised in the algorithm parameter.
+const (
+	AlgorithmMD5            = "MD5"
+	AlgorithmMD5Sess        = "MD5-sess"
+	AlgorithmSHA256         = "SHA-256"
+	AlgorithmSHA256Sess     = "SHA-256-sess"
+	AlgorithmSHA512_256     = "SHA-512-256"
+	AlgorithmSHA512_256Sess = "SHA-512-256-sess"
+)
+
+const (
+	qopAuth    = "auth"
+	qopAuthInt = "auth-int"
+	sessSuffix = "-sess"
+)
+
+// algorithmStrength ranks the supported hash functions; higher is stronger.
+var algorithmStrength = map[string]int{
+	AlgorithmMD5:        1,
+	AlgorithmSHA256:     2,
+	AlgorithmSHA512_256: 3,
+}
+
+// DigestAuth implements HTTP Digest Authentication according to RFC 7616.
 // It handles the challenge-response authentication flow required by TiDB Cloud API.
+// Once a challenge has been parsed its nonce is reused for subsequent requests,
+// with the nonce count incremented for each one, until the server issues a new
+// challenge. A DigestAuth is safe for concurrent use.
 type DigestAuth struct {
+	mu sync.Mutex
+	digestParams
+}
+
+// digestParams is the state a response is computed from. GenerateAuthHeader
+// works on a copy so the lock is not held while hashing a request body.
+type digestParams struct {
 	realm     string // Authentication realm from server
 	nonce     string // Server-provided nonce value
-	qop       string // Quality of protection (typically "auth")
+	qop       string // Chosen quality of protection ("auth", "auth-int" or empty)
 	opaque    string // Opaque value from server
-	algorithm string // Hash algorithm (typically "MD5")
-	nc        int    // Nonce count for replay protection
+	algorithm string // Chosen hash algorithm, e.g. "MD5" or "SHA-256-sess"
+	nc        int    // Number of requests sent with the current nonce
 	cnonce    string // Client-generated nonce
 }
 
 // NewDigestAuth creates a new DigestAuth instance.
-// The instance is initialized with a nonce count of 1 and is ready
-// to parse authentication challenges from the server.
+// The instance has no nonce yet and is ready to parse authentication
+// challenges from the server.
 func NewDigestAuth() *DigestAuth {
-	return &DigestAuth{
-		nc: 1,
-	}
+	return &DigestAuth{}
 }
 
 // ParseChallenge parses an HTTP Digest authentication challenge from the server.
 // It extracts the realm, nonce, qop, opaque, and algorithm values from the
 // WWW-Authenticate header and prepares the client for response generation.
+// A header carrying several challenges is handled as by ParseChallenges.
 func (d *DigestAuth) ParseChallenge(authHeader string) error {
-	if authHeader == "" {
-		return errors.New("empty auth header")
+	_, err := d.ParseChallenges([]string{authHeader})
+	return err
+}
+
+// ParseChallenges parses the WWW-Authenticate headers of a 401 response, which
+// may offer several Digest challenges, one per algorithm. The challenge with
+// the strongest supported algorithm is used. When it offers both auth and
+// auth-int, auth is chosen.
+//
+// Returns:
+//   - bool: true if the server marked the previous nonce as stale, meaning the
+//     credentials were accepted and the request can be resent with the new nonce
+//   - error: An error if no usable Digest challenge was found
+func (d *DigestAuth) ParseChallenges(authHeaders []string) (bool, error) {
+	_, stale, err := d.parseChallenges(authHeaders)
+	return stale, err
+}
+
+// parseChallenges is ParseChallenges, also returning the nonce of the chosen challenge.
+func (d *DigestAuth) parseChallenges(authHeaders []string) (string, bool, error) {
+	var challenges []map[string]string
+	for _, header := range authHeaders {
+		for _, challenge := range splitChallenges(header) {
+			challenges = append(challenges, parseKeyValuePairs(challenge))
+		}
+	}
+	if len(challenges) == 0 {
+		if len(authHeaders) == 0 || strings.Join(authHeaders, "") == "" {
+			return "", false, errors.New("empty auth header")
+		}
+		return "", false, errors.New("not a digest auth header")
 	}
 
-	if !strings.HasPrefix(authHeader, "Digest ") {
-		return errors.New("not a digest auth header")
+	var best map[string]string
+	var lastErr error
+	for _, pairs := range challenges {
+		if pairs["realm"] == "" {
+			lastErr = errors.New("missing realm in digest challenge")
+			continue
+		}
+		if pairs["nonce"] == "" {
+			lastErr = errors.New("missing nonce in digest challenge")
+			continue
+		}
+		if pairs["algorithm"] == "" {
+			pairs["algorithm"] = AlgorithmMD5
+		}
+		strength, ok := algorithmStrength[baseAlgorithm(pairs["algorithm"])]
+		if !ok {
+			lastErr = fmt.Errorf("unsupported digest algorithm %q", pairs["algorithm"])
+			continue
+		}
+		if best == nil || strength > algorithmStrength[baseAlgorithm(best["algorithm"])] {
+			best = pairs
+		}
+	}
+	if best == nil {
+		return "", false, lastErr
 	}
 
-	challengeData := strings.TrimPrefix(authHeader, "Digest ")
+	qop, err := chooseQop(best["qop"])
+	if err != nil {
+		return "", false, err
+	}
 
-	// Parse key-value pairs
-	pairs := parseKeyValuePairs(challengeData)
+	d.mu.Lock()
+	defer d.mu.Unlock()
 
-	d.realm = pairs["realm"]
-	d.nonce = pairs["nonce"]
-	d.qop = pairs["qop"]
-	d.opaque = pairs["opaque"]
-	d.algorithm = pairs["algorithm"]
+	d.realm = best["realm"]
+	d.qop = qop
+	d.opaque = best["opaque"]
+	d.algorithm = best["algorithm"]
 
-	if d.algorithm == "" {
-		d.algorithm = "MD5"
+	// A new nonce restarts the nonce count and gets a fresh cnonce
+	if best["nonce"] != d.nonce || d.cnonce == "" {
+		d.nonce = best["nonce"]
+		d.nc = 0
+		d.cnonce = generateCnonce()
 	}
 
-	if d.realm == "" {
-		return errors.New("missing realm in digest challenge")
-	}
-	if d.nonce == "" {
-		return errors.New("missing nonce in digest challenge")
-	}
+	return best["nonce"], strings.EqualFold(best["stale"], "true"), nil
+}
 
-	// Generate cnonce for this auth
-	d.cnonce = generateCnonce()
+// Reset discards the parsed challenge, e.g. after the credentials changed, so
+// the next request is sent unauthenticated to obtain a fresh one.
+func (d *DigestAuth) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.digestParams = digestParams{}
+}
+
+// HasChallenge reports whether a challenge has been parsed, so that
+// GenerateAuthHeader can authenticate a request before it is sent.
+func (d *DigestAuth) HasChallenge() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.nonce != ""
+}
 
-	return nil
+// NeedsBody reports whether the current challenge uses qop=auth-int, in which
+// case the request body must be passed to GenerateAuthHeaderWithBody.
+func (d *DigestAuth) NeedsBody() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.qop == qopAuthInt
 }
 
 // GenerateAuthHeader generates the Authorization header value for HTTP Digest authentication.
 // It creates the digest response using the provided credentials and request details,
-// following the RFC 2617 specification for digest calculation.
+// following the RFC 7616 specification for digest calculation.
+// Each call increments the nonce count, so every header is single-use.
+// With qop=auth-int the request is treated as having an empty body.
 //
 // Parameters:
 //   - username: The API public key
@@ -86,79 +199,202 @@ func (d *DigestAuth) ParseChallenge(authHeader string) error {
 // Returns:
 //   - string: Complete Authorization header value, or empty string if not ready
 func (d *DigestAuth) GenerateAuthHeader(username, password, method, uri string) string {
+	header, _ := d.GenerateAuthHeaderWithBody(username, password, method, uri, nil)
+	return header
+}
+
+// GenerateAuthHeaderWithBody is like GenerateAuthHeader, but hashes body into
+// the response when the challenge uses qop=auth-int. A nil body is treated as
+// empty. The body is only read when it is needed.
+//
+// Returns:
+//   - string: Complete Authorization header value, or empty string if not ready
+//   - error: An error if the body cannot be read
+func (d *DigestAuth) GenerateAuthHeaderWithBody(username, password, method, uri string, body io.Reader) (string, error) {
+	p, ok := d.next()
+	if !ok {
+		return "", nil
+	}
+	return p.authHeader(username, password, method, uri, body)
+}
+
+// next increments the nonce count and returns a snapshot of the parameters to
+// compute a single response from, taken under one lock. It returns false if no
+// challenge has been parsed.
+func (d *DigestAuth) next() (digestParams, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	if d.nonce == "" {
-		return ""
+		return digestParams{}, false
+	}
+	if d.cnonce == "" {
+		d.cnonce = generateCnonce()
+	}
+	if d.algorithm == "" {
+		d.algorithm = AlgorithmMD5
+	}
+	d.nc++
+	return d.digestParams, true
+}
+
+func (p *digestParams) authHeader(username, password, method, uri string, body io.Reader) (string, error) {
+	if _, ok := algorithmStrength[baseAlgorithm(p.algorithm)]; !ok {
+		return "", fmt.Errorf("unsupported digest algorithm %q", p.algorithm)
+	}
+
+	var bodyHash string
+	if p.qop == qopAuthInt {
+		h := p.newHash()
+		if body != nil {
+			if _, err := io.Copy(h, body); err != nil {
+				return "", fmt.Errorf("failed to hash request body: %w", err)
+			}
+		}
+		bodyHash = fmt.Sprintf("%x", h.Sum(nil))
 	}
 
-	ha1 := d.generateHA1(username, password)
-	ha2 := d.generateHA2(method, uri)
+	ha1 := p.generateHA1(username, password)
+	ha2 := p.generateHA2(method, uri, bodyHash)
 
 	var response string
-	if d.qop == "auth" {
-		response = d.generateResponseWithQop(ha1, ha2)
+	if p.qop != "" {
+		response = p.generateResponseWithQop(ha1, ha2)
 	} else {
-		response = d.generateResponseWithoutQop(ha1, ha2)
+		response = p.generateResponseWithoutQop(ha1, ha2)
 	}
 
 	var authHeader strings.Builder
 	authHeader.WriteString("Digest ")
 	authHeader.WriteString(fmt.Sprintf(`username="%s"`, username))
-	authHeader.WriteString(fmt.Sprintf(`, realm="%s"`, d.realm))
-	authHeader.WriteString(fmt.Sprintf(`, nonce="%s"`, d.nonce))
+	authHeader.WriteString(fmt.Sprintf(`, realm="%s"`, p.realm))
+	authHeader.WriteString(fmt.Sprintf(`, nonce="%s"`, p.nonce))
 	authHeader.WriteString(fmt.Sprintf(`, uri="%s"`, uri))
 	authHeader.WriteString(fmt.Sprintf(`, response="%s"`, response))
 
-	if d.qop != "" {
-		authHeader.WriteString(fmt.Sprintf(`, qop=%s`, d.qop))
-		authHeader.WriteString(fmt.Sprintf(`, nc=%08x`, d.nc))
-		authHeader.WriteString(fmt.Sprintf(`, cnonce="%s"`, d.cnonce))
+	if p.qop != "" {
+		authHeader.WriteString(fmt.Sprintf(`, qop=%s`, p.qop))
+		authHeader.WriteString(fmt.Sprintf(`, nc=%08x`, p.nc))
+		authHeader.WriteString(fmt.Sprintf(`, cnonce="%s"`, p.cnonce))
 	}
 
-	if d.opaque != "" {
-		authHeader.WriteString(fmt.Sprintf(`, opaque="%s"`, d.opaque))
+	if p.opaque != "" {
+		authHeader.WriteString(fmt.Sprintf(`, opaque="%s"`, p.opaque))
 	}
 
-	if d.algorithm != "" {
-		authHeader.WriteString(fmt.Sprintf(`, algorithm=%s`, d.algorithm))
-	}
+	authHeader.WriteString(fmt.Sprintf(`, algorithm=%s`, p.algorithm))
 
-	return authHeader.String()
+	return authHeader.String(), nil
 }
 
-func (d *DigestAuth) generateHA1(username, password string) string {
-	h := md5.New()
-	h.Write([]byte(fmt.Sprintf("%s:%s:%s", username, d.realm, password)))
-	return fmt.Sprintf("%x", h.Sum(nil))
+func (p *digestParams) newHash() hash.Hash {
+	switch baseAlgorithm(p.algorithm) {
+	case AlgorithmSHA256:
+		return sha256.New()
+	case AlgorithmSHA512_256:
+		return sha512.New512_256()
+	default:
+		return md5.New()
+	}
 }
 
-func (d *DigestAuth) generateHA2(method, uri string) string {
-	h := md5.New()
-	h.Write([]byte(fmt.Sprintf("%s:%s", method, uri)))
+func (p *digestParams) digest(data string) string {
+	h := p.newHash()
+	h.Write([]byte(data))
 	return fmt.Sprintf("%x", h.Sum(nil))
 }
 
-func (d *DigestAuth) generateResponseWithQop(ha1, ha2 string) string {
-	h := md5.New()
-	h.Write([]byte(fmt.Sprintf("%s:%s:%08x:%s:%s:%s",
-		ha1, d.nonce, d.nc, d.cnonce, d.qop, ha2)))
-	return fmt.Sprintf("%x", h.Sum(nil))
+func (p *digestParams) generateHA1(username, password string) string {
+	ha1 := p.digest(fmt.Sprintf("%s:%s:%s", username, p.realm, password))
+	if strings.HasSuffix(strings.ToLower(p.algorithm), sessSuffix) {
+		ha1 = p.digest(fmt.Sprintf("%s:%s:%s", ha1, p.nonce, p.cnonce))
+	}
+	return ha1
 }
 
-func (d *DigestAuth) generateResponseWithoutQop(ha1, ha2 string) string {
-	h := md5.New()
-	h.Write([]byte(fmt.Sprintf("%s:%s:%s", ha1, d.nonce, ha2)))
-	return fmt.Sprintf("%x", h.Sum(nil))
+func (p *digestParams) generateHA2(method, uri, bodyHash string) string {
+	if p.qop == qopAuthInt {
+		return p.digest(fmt.Sprintf("%s:%s:%s", method, uri, bodyHash))
+	}
+	return p.digest(fmt.Sprintf("%s:%s", method, uri))
+}
+
+func (p *digestParams) generateResponseWithQop(ha1, ha2 string) string {
+	return p.digest(fmt.Sprintf("%s:%s:%08x:%s:%s:%s",
+		ha1, p.nonce, p.nc, p.cnonce, p.qop, ha2))
+}
+
+func (p *digestParams) generateResponseWithoutQop(ha1, ha2 string) string {
+	return p.digest(fmt.Sprintf("%s:%s:%s", ha1, p.nonce, ha2))
+}
+
+// baseAlgorithm returns the canonical hash name of an algorithm, without any
+// -sess suffix, or the input unchanged if it is not supported.
+func baseAlgorithm(algorithm string) string {
+	name := algorithm
+	if len(name) > len(sessSuffix) && strings.EqualFold(name[len(name)-len(sessSuffix):], sessSuffix) {
+		name = name[:len(name)-len(sessSuffix)]
+	}
+	for supported := range algorithmStrength {
+		if strings.EqualFold(name, supported) {
+			return supported
+		}
+	}
+	return algorithm
+}
+
+// chooseQop picks auth over auth-int from a challenge's comma-separated qop
+// list. An absent qop selects the RFC 2069 compatible response.
+func chooseQop(offered string) (string, error) {
+	if offered == "" {
+		return "", nil
+	}
+
+	var authInt bool
+	for _, qop := range strings.Split(offered, ",") {
+		switch strings.ToLower(strings.TrimSpace(qop)) {
+		case qopAuth:
+			return qopAuth, nil
+		case qopAuthInt:
+			authInt = true
+		}
+	}
+	if authInt {
+		return qopAuthInt, nil
+	}
+	return "", fmt.Errorf("unsupported digest qop %q", offered)
+}
+
+// splitChallenges splits a WWW-Authenticate header into the parameter lists of
+// its Digest challenges, skipping challenges of other schemes.
+func splitChallenges(header string) []string {
+	starts := challengeStartRe.FindAllStringSubmatchIndex(header, -1)
+
+	var challenges []string
+	for i, start := range starts {
+		end := len(header)
+		if i+1 < len(starts) {
+			end = starts[i+1][0]
+		}
+		if strings.EqualFold(header[start[2]:start[3]], "Digest") {
+			challenges = append(challenges, header[start[3]:end])
+		}
+	}
+	return challenges
 }
 
+// challengeStartRe matches an auth scheme token at the start of the header or
+// after a comma, i.e. the start of a challenge rather than a parameter.
+var challengeStartRe = regexp.MustCompile(`(?:^|,)\s*([A-Za-z][A-Za-z0-9_-]*)(?:\s+[A-Za-z0-9_-]+=|\s*$)`)
+
 func parseKeyValuePairs(data string) map[string]string {
 	pairs := make(map[string]string)
 
 	// Use regex to find key="value" pairs
-	re := regexp.MustCompile(`(\w+)=(?:"([^"]*)"|([^,\s]+))`)
+	re := regexp.MustCompile(`([\w-]+)=(?:"([^"]*)"|([^,\s]+))`)
 	matches := re.FindAllStringSubmatch(data, -1)
 
 	for _, match := range matches {
-		key := match[1]
+		key := strings.ToLower(match[1])
 		value := match[2]
 		if value == "" {
 			value = match[3]
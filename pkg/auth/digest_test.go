@@ -1,6 +1,10 @@
 package auth
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -149,6 +153,272 @@ func TestDigestAuth_GenerateAuthHeader(t *testing.T) {
 	}
 }
 
+func TestDigestAuth_NonceCount(t *testing.T) {
+	auth := NewDigestAuth()
+	if auth.HasChallenge() {
+		t.Fatal("HasChallenge() = true before any challenge")
+	}
+
+	challenge := `Digest realm="tidbcloud", nonce="nonce-1", qop="auth"`
+	if err := auth.ParseChallenge(challenge); err != nil {
+		t.Fatalf("ParseChallenge() unexpected error: %v", err)
+	}
+	if !auth.HasChallenge() {
+		t.Fatal("HasChallenge() = false after challenge")
+	}
+
+	for i := 1; i <= 3; i++ {
+		header := auth.GenerateAuthHeader("testuser", "testpass", "GET", "/api/v1beta/projects")
+		if nc := nonceCount(header); nc != fmt.Sprintf("%08x", i) {
+			t.Errorf("request %d: nc = %s, want %08x", i, nc, i)
+		}
+	}
+
+	// The same nonce continues the count
+	if err := auth.ParseChallenge(challenge); err != nil {
+		t.Fatalf("ParseChallenge() unexpected error: %v", err)
+	}
+	if nc := nonceCount(auth.GenerateAuthHeader("testuser", "testpass", "GET", "/")); nc != "00000004" {
+		t.Errorf("same nonce: nc = %s, want 00000004", nc)
+	}
+
+	// A new nonce restarts it
+	if err := auth.ParseChallenge(`Digest realm="tidbcloud", nonce="nonce-2", qop="auth"`); err != nil {
+		t.Fatalf("ParseChallenge() unexpected error: %v", err)
+	}
+	if nc := nonceCount(auth.GenerateAuthHeader("testuser", "testpass", "GET", "/")); nc != "00000001" {
+		t.Errorf("new nonce: nc = %s, want 00000001", nc)
+	}
+
+	// An invalid challenge leaves the current nonce in place
+	if err := auth.ParseChallenge(`Digest realm="tidbcloud"`); err == nil {
+		t.Fatal("ParseChallenge() expected error for missing nonce")
+	}
+	if nc := nonceCount(auth.GenerateAuthHeader("testuser", "testpass", "GET", "/")); nc != "00000002" {
+		t.Errorf("after invalid challenge: nc = %s, want 00000002", nc)
+	}
+}
+
+func TestDigestAuth_Concurrent(t *testing.T) {
+	auth := NewDigestAuth()
+	if err := auth.ParseChallenge(`Digest realm="tidbcloud", nonce="nonce-1", qop="auth"`); err != nil {
+		t.Fatalf("ParseChallenge() unexpected error: %v", err)
+	}
+
+	const goroutines = 50
+	const perGoroutine = 20
+
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				nc := nonceCount(auth.GenerateAuthHeader("testuser", "testpass", "GET", "/api/v1beta/projects"))
+				mu.Lock()
+				if seen[nc] {
+					t.Errorf("nc %s used twice", nc)
+				}
+				seen[nc] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != goroutines*perGoroutine {
+		t.Errorf("Expected %d distinct nonce counts, got %d", goroutines*perGoroutine, len(seen))
+	}
+}
+
+func TestDigestAuth_Algorithms(t *testing.T) {
+	// Example from RFC 7616 section 3.9.1
+	rfcParams := digestParams{
+		realm:  "http-auth@example.org",
+		nonce:  "7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v",
+		opaque: "FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS",
+		qop:    "auth",
+		nc:     1,
+		cnonce: "f2/wE4q74E6zIJEtWaHKaf5wv/H5QzzpXusqGemxURZJ",
+	}
+
+	tests := []struct {
+		name             string
+		algorithm        string
+		qop              string
+		expectedResponse string
+	}{
+		{
+			name:             "RFC 7616 MD5",
+			algorithm:        AlgorithmMD5,
+			qop:              "auth",
+			expectedResponse: "8ca523f5e9506fed4657c9700eebdbec",
+		},
+		{
+			name:             "RFC 7616 SHA-256",
+			algorithm:        AlgorithmSHA256,
+			qop:              "auth",
+			expectedResponse: "753927fa0e85d155564e2e272a28d1802ca10daf4496794697cf8db5856cb6c1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := rfcParams
+			p.algorithm = tt.algorithm
+			p.qop = tt.qop
+
+			header, err := p.authHeader("Mufasa", "Circle of Life", "GET", "/dir/index.html", nil)
+			if err != nil {
+				t.Fatalf("authHeader() unexpected error: %v", err)
+			}
+			if !strings.Contains(header, fmt.Sprintf(`response="%s"`, tt.expectedResponse)) {
+				t.Errorf("authHeader() = %s, want response %s", header, tt.expectedResponse)
+			}
+			if !strings.Contains(header, "algorithm="+tt.algorithm) {
+				t.Errorf("authHeader() = %s, want algorithm %s", header, tt.algorithm)
+			}
+		})
+	}
+
+	t.Run("sess variant hashes nonce and cnonce into HA1", func(t *testing.T) {
+		p := rfcParams
+		p.algorithm = AlgorithmSHA256
+		plain := p.generateHA1("Mufasa", "Circle of Life")
+
+		p.algorithm = AlgorithmSHA256Sess
+		expected := p.digest(fmt.Sprintf("%s:%s:%s", plain, p.nonce, p.cnonce))
+		if got := p.generateHA1("Mufasa", "Circle of Life"); got != expected {
+			t.Errorf("generateHA1() = %s, want %s", got, expected)
+		}
+	})
+
+	t.Run("auth-int hashes the body into HA2", func(t *testing.T) {
+		p := rfcParams
+		p.algorithm = AlgorithmSHA512_256
+		p.qop = "auth-int"
+
+		withBody, err := p.authHeader("Mufasa", "Circle of Life", "POST", "/dir/index.html", strings.NewReader(`{"name":"a"}`))
+		if err != nil {
+			t.Fatalf("authHeader() unexpected error: %v", err)
+		}
+		empty, err := p.authHeader("Mufasa", "Circle of Life", "POST", "/dir/index.html", nil)
+		if err != nil {
+			t.Fatalf("authHeader() unexpected error: %v", err)
+		}
+		if withBody == empty {
+			t.Error("authHeader() response does not depend on the body")
+		}
+		if !strings.Contains(withBody, "qop=auth-int") {
+			t.Errorf("authHeader() = %s, want qop=auth-int", withBody)
+		}
+	})
+}
+
+func TestDigestAuth_ParseChallenges(t *testing.T) {
+	tests := []struct {
+		name          string
+		headers       []string
+		expectedAlg   string
+		expectedQop   string
+		expectedNonce string
+		expectedStale bool
+		expectedErr   string
+	}{
+		{
+			name: "strongest algorithm across headers",
+			headers: []string{
+				`Digest realm="tidbcloud", nonce="n-md5", qop="auth", algorithm=MD5`,
+				`Digest realm="tidbcloud", nonce="n-sha", qop="auth", algorithm=SHA-256`,
+			},
+			expectedAlg:   AlgorithmSHA256,
+			expectedQop:   "auth",
+			expectedNonce: "n-sha",
+		},
+		{
+			name: "several challenges in one header",
+			headers: []string{
+				`Basic realm="other", Digest realm="tidbcloud", nonce="n-sha", qop="auth", algorithm=SHA-512-256-sess, Digest realm="tidbcloud", nonce="n-md5", qop="auth"`,
+			},
+			expectedAlg:   AlgorithmSHA512_256Sess,
+			expectedQop:   "auth",
+			expectedNonce: "n-sha",
+		},
+		{
+			name:          "auth preferred over auth-int",
+			headers:       []string{`Digest realm="tidbcloud", nonce="n1", qop="auth-int,auth"`},
+			expectedAlg:   AlgorithmMD5,
+			expectedQop:   "auth",
+			expectedNonce: "n1",
+		},
+		{
+			name:          "auth-int only",
+			headers:       []string{`Digest realm="tidbcloud", nonce="n1", qop="auth-int", algorithm=MD5-sess`},
+			expectedAlg:   AlgorithmMD5Sess,
+			expectedQop:   "auth-int",
+			expectedNonce: "n1",
+		},
+		{
+			name:          "stale nonce",
+			headers:       []string{`Digest realm="tidbcloud", nonce="n2", qop="auth", stale=TRUE`},
+			expectedAlg:   AlgorithmMD5,
+			expectedQop:   "auth",
+			expectedNonce: "n2",
+			expectedStale: true,
+		},
+		{
+			name:        "unsupported algorithm",
+			headers:     []string{`Digest realm="tidbcloud", nonce="n1", algorithm=SHA-1`},
+			expectedErr: `unsupported digest algorithm "SHA-1"`,
+		},
+		{
+			name:        "unsupported qop",
+			headers:     []string{`Digest realm="tidbcloud", nonce="n1", qop="auth-conf"`},
+			expectedErr: `unsupported digest qop "auth-conf"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := NewDigestAuth()
+			stale, err := auth.ParseChallenges(tt.headers)
+
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("ParseChallenges() error = %v, want %q", err, tt.expectedErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseChallenges() unexpected error: %v", err)
+			}
+			if stale != tt.expectedStale {
+				t.Errorf("ParseChallenges() stale = %v, want %v", stale, tt.expectedStale)
+			}
+			if auth.algorithm != tt.expectedAlg {
+				t.Errorf("ParseChallenges() algorithm = %v, want %v", auth.algorithm, tt.expectedAlg)
+			}
+			if auth.qop != tt.expectedQop {
+				t.Errorf("ParseChallenges() qop = %v, want %v", auth.qop, tt.expectedQop)
+			}
+			if auth.nonce != tt.expectedNonce {
+				t.Errorf("ParseChallenges() nonce = %v, want %v", auth.nonce, tt.expectedNonce)
+			}
+		})
+	}
+}
+
+func nonceCount(header string) string {
+	m := regexp.MustCompile(`nc=([0-9a-f]{8})`).FindStringSubmatch(header)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
 func containsDigestFields(header string) bool {
 	return len(header) > 0 &&
 		header[:6] == "Digest" &&
@@ -2,30 +2,39 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
+	"time"
 
+	"github.com/5st7/tidb-cloud-go/pkg/errors"
 	"github.com/5st7/tidb-cloud-go/pkg/models"
 )
 
-// ListBackups lists all backups for a cluster
+// ListBackups lists the first page of backups for a cluster
 func (c *Client) ListBackups(projectID, clusterID string) (*models.OpenapiListBackupOfClusterResp, error) {
+	return c.ListBackupsWithOptions(context.Background(), projectID, clusterID, nil)
+}
+
+// ListBackupsWithOptions lists one page of backups for a cluster
+func (c *Client) ListBackupsWithOptions(ctx context.Context, projectID, clusterID string, opts *ListOptions) (*models.OpenapiListBackupOfClusterResp, error) {
 	if projectID == "" {
-		return nil, fmt.Errorf("project ID is required")
+		return nil, errors.Required("project ID")
 	}
 	if clusterID == "" {
-		return nil, fmt.Errorf("cluster ID is required")
+		return nil, errors.Required("cluster ID")
 	}
 
-	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/backups", c.baseURL, APIVersion, projectID, clusterID)
+	url := withListOptions(fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/backups", c.baseURL, c.apiVersion, projectID, clusterID), opts)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.doRequest(req)
+	resp, err := c.doRequestWithRetry(ctx, OpListBackups, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -43,26 +52,42 @@ func (c *Client) ListBackups(projectID, clusterID string) (*models.OpenapiListBa
 	return &backups, nil
 }
 
+// AllBackups iterates over every backup of a cluster, page by page
+func (c *Client) AllBackups(ctx context.Context, projectID, clusterID string) iter.Seq2[*models.OpenapiListBackupItem, error] {
+	return paginate(ctx, func(ctx context.Context, opts *ListOptions) ([]*models.OpenapiListBackupItem, int64, error) {
+		resp, err := c.ListBackupsWithOptions(ctx, projectID, clusterID, opts)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp.Items, totalValue(resp.Total), nil
+	})
+}
+
 // GetBackup gets a backup by ID
 func (c *Client) GetBackup(projectID, clusterID, backupID string) (*models.OpenapiGetBackupOfClusterResp, error) {
+	return c.GetBackupWithContext(context.Background(), projectID, clusterID, backupID)
+}
+
+// GetBackupWithContext gets a backup by ID using ctx for cancellation and deadlines
+func (c *Client) GetBackupWithContext(ctx context.Context, projectID, clusterID, backupID string) (*models.OpenapiGetBackupOfClusterResp, error) {
 	if projectID == "" {
-		return nil, fmt.Errorf("project ID is required")
+		return nil, errors.Required("project ID")
 	}
 	if clusterID == "" {
-		return nil, fmt.Errorf("cluster ID is required")
+		return nil, errors.Required("cluster ID")
 	}
 	if backupID == "" {
-		return nil, fmt.Errorf("backup ID is required")
+		return nil, errors.Required("backup ID")
 	}
 
-	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/backups/%s", c.baseURL, APIVersion, projectID, clusterID, backupID)
+	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/backups/%s", c.baseURL, c.apiVersion, projectID, clusterID, backupID)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.doRequest(req)
+	resp, err := c.doRequestWithRetry(ctx, OpGetBackup, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -82,17 +107,22 @@ func (c *Client) GetBackup(projectID, clusterID, backupID string) (*models.Opena
 
 // CreateBackup creates a new backup
 func (c *Client) CreateBackup(projectID, clusterID string, req *models.OpenapiCreateBackupReq) (*models.OpenapiCreateBackupResp, error) {
+	return c.CreateBackupWithContext(context.Background(), projectID, clusterID, req)
+}
+
+// CreateBackupWithContext creates a new backup using ctx for cancellation and deadlines
+func (c *Client) CreateBackupWithContext(ctx context.Context, projectID, clusterID string, req *models.OpenapiCreateBackupReq) (*models.OpenapiCreateBackupResp, error) {
 	if projectID == "" {
-		return nil, fmt.Errorf("project ID is required")
+		return nil, errors.Required("project ID")
 	}
 	if clusterID == "" {
-		return nil, fmt.Errorf("cluster ID is required")
+		return nil, errors.Required("cluster ID")
 	}
 	if req == nil {
-		return nil, fmt.Errorf("request is required")
+		return nil, errors.Required("request")
 	}
 
-	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/backups", c.baseURL, APIVersion, projectID, clusterID)
+	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/backups", c.baseURL, c.apiVersion, projectID, clusterID)
 
 	reqBody, err := json.Marshal(req)
 	if err != nil {
@@ -105,10 +135,25 @@ func (c *Client) CreateBackup(projectID, clusterID string, req *models.OpenapiCr
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.doRequest(httpReq)
+	// Before resending, look for the backup in case an earlier attempt created it
+	since := time.Now()
+	var created *models.OpenapiCreateBackupResp
+	var reconcile reconcileFunc
+	if req.Name != nil {
+		reconcile = func(ctx context.Context) (bool, error) {
+			var err error
+			created, err = c.findCreatedBackup(ctx, projectID, clusterID, req, since)
+			return created != nil, err
+		}
+	}
+
+	resp, reconciled, err := c.doRequestWithReconcile(ctx, OpCreateBackup, httpReq, reconcile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
+	if reconciled {
+		return created, nil
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
@@ -125,27 +170,35 @@ func (c *Client) CreateBackup(projectID, clusterID string, req *models.OpenapiCr
 
 // DeleteBackup deletes a backup
 func (c *Client) DeleteBackup(projectID, clusterID, backupID string) error {
+	return c.DeleteBackupWithContext(context.Background(), projectID, clusterID, backupID)
+}
+
+// DeleteBackupWithContext deletes a backup using ctx for cancellation and deadlines
+func (c *Client) DeleteBackupWithContext(ctx context.Context, projectID, clusterID, backupID string) error {
 	if projectID == "" {
-		return fmt.Errorf("project ID is required")
+		return errors.Required("project ID")
 	}
 	if clusterID == "" {
-		return fmt.Errorf("cluster ID is required")
+		return errors.Required("cluster ID")
 	}
 	if backupID == "" {
-		return fmt.Errorf("backup ID is required")
+		return errors.Required("backup ID")
 	}
 
-	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/backups/%s", c.baseURL, APIVersion, projectID, clusterID, backupID)
+	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/backups/%s", c.baseURL, c.apiVersion, projectID, clusterID, backupID)
 
 	req, err := http.NewRequest("DELETE", url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.doRequest(req)
+	resp, reconciled, err := c.doRequestWithReconcile(ctx, OpDeleteBackup, req, nil)
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
 	}
+	if reconciled {
+		return nil
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
@@ -7,22 +7,24 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/5st7/tidb-cloud-go/pkg/auth"
 	"github.com/5st7/tidb-cloud-go/pkg/errors"
 	"github.com/5st7/tidb-cloud-go/pkg/models"
+	"github.com/5st7/tidb-cloud-go/pkg/ratelimit"
 	"github.com/5st7/tidb-cloud-go/pkg/retry"
 )
 
 const (
 	// DefaultBaseURL is the default TiDB Cloud API base URL
 	DefaultBaseURL = "https://api.tidbcloud.com"
-	// APIVersion is the current API version used by the client
+	// APIVersion is the default API version used by the client
 	APIVersion = "v1beta"
 )
 
@@ -30,60 +32,154 @@ const (
 // It handles authentication, retries, and error handling for all API operations.
 type Client struct {
 	baseURL       string
+	apiVersion    string
+	userAgent     string
 	httpClient    *http.Client
-	publicKey     string
-	privateKey    string
-	digestAuth    *auth.DigestAuth
+	timeout       time.Duration
+	authenticator auth.Authenticator
 	retryExecutor *retry.RetryExecutor
+	rateLimiter   *ratelimit.Limiter
+
+	// circuitBreaker is applied to every retry executor once the options are set
+	circuitBreaker *retry.CircuitBreaker
+
+	// operationRetry holds the executors of operations with their own retry policy
+	operationRetry map[Operation]*retry.RetryExecutor
 }
 
 // NewClient creates a new TiDB Cloud API client with the provided credentials.
 // The client is configured with default settings including a 30-second timeout,
 // automatic retry with exponential backoff, and HTTP Digest Authentication.
+// Options such as WithBaseURL or WithHTTPClient override these defaults.
+// With WithAuthenticator the key pair is not used and may be empty.
 //
 // Parameters:
 //   - publicKey: Your TiDB Cloud API public key
 //   - privateKey: Your TiDB Cloud API private key
+//   - opts: Options applied in order on top of the defaults
 //
 // Returns:
 //   - *Client: A configured TiDB Cloud client
-//   - error: An error if the credentials are invalid
-func NewClient(publicKey, privateKey string) (*Client, error) {
-	if publicKey == "" {
-		return nil, fmt.Errorf("public key is required")
-	}
-	if privateKey == "" {
-		return nil, fmt.Errorf("private key is required")
-	}
-
+//   - error: An error if the credentials or an option are invalid
+func NewClient(publicKey, privateKey string, opts ...Option) (*Client, error) {
 	retryPolicy := retry.NewRetryPolicy()
 	retryExecutor := retry.NewRetryExecutor(retryPolicy)
 
-	return &Client{
+	c := &Client{
 		baseURL:       DefaultBaseURL,
-		httpClient:    &http.Client{Timeout: 30 * time.Second},
-		publicKey:     publicKey,
-		privateKey:    privateKey,
-		digestAuth:    auth.NewDigestAuth(),
+		apiVersion:    APIVersion,
+		userAgent:     DefaultUserAgent,
+		httpClient:    &http.Client{Timeout: DefaultTimeout},
 		retryExecutor: retryExecutor,
-	}, nil
+	}
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.circuitBreaker != nil {
+		c.retryExecutor = c.retryExecutor.WithCircuitBreaker(c.circuitBreaker)
+		for op, executor := range c.operationRetry {
+			c.operationRetry[op] = executor.WithCircuitBreaker(c.circuitBreaker)
+		}
+	}
+
+	if c.authenticator == nil {
+		digest, err := auth.NewDigestAuthenticator(publicKey, privateKey)
+		if err != nil {
+			return nil, err
+		}
+		c.authenticator = digest
+	}
+
+	// Apply the timeout to a copy so a caller-supplied client is not modified
+	if c.timeout > 0 {
+		httpClient := *c.httpClient
+		httpClient.Timeout = c.timeout
+		c.httpClient = &httpClient
+	}
+
+	return c, nil
+}
+
+// NewClientFromProfile creates a client with credentials from a profile of the
+// credentials file (see auth.ProfileProvider). An empty profile uses the
+// default chain instead: the TIDBCLOUD_PUBLIC_KEY and TIDBCLOUD_PRIVATE_KEY
+// environment variables, then the profile named by TIDBCLOUD_PROFILE or "default".
+//
+// Parameters:
+//   - profile: The profile name, or empty for the default chain
+//   - opts: Options applied as by NewClient
+//
+// Returns:
+//   - *Client: A configured TiDB Cloud client
+//   - error: An error if no credentials are found or an option is invalid
+func NewClientFromProfile(profile string, opts ...Option) (*Client, error) {
+	var provider auth.CredentialsProvider = auth.DefaultCredentialsChain()
+	if profile != "" {
+		provider = auth.ProfileProvider{Profile: profile}
+	}
+	return NewClientFromProvider(context.Background(), provider, opts...)
+}
+
+// NewClientFromProvider creates a client with credentials from provider, e.g.
+// an auth.ChainProvider. The provider is consulted again whenever the cached
+// credentials expire, as with WithCredentialsProvider, so rotated keys are
+// picked up by the running client.
+//
+// Parameters:
+//   - ctx: Context bounding the initial credential lookup, e.g. a credential_process command
+//   - provider: The credentials provider
+//   - opts: Options applied as by NewClient
+//
+// Returns:
+//   - *Client: A configured TiDB Cloud client
+//   - error: An error if the credentials cannot be retrieved or an option is invalid
+func NewClientFromProvider(ctx context.Context, provider auth.CredentialsProvider, opts ...Option) (*Client, error) {
+	if provider == nil {
+		return nil, errors.Required("credentials provider")
+	}
+
+	// Retrieve once up front so a missing configuration fails construction
+	cache := auth.NewCredentialsCache(provider, 0)
+	if _, err := cache.Retrieve(ctx); err != nil {
+		return nil, fmt.Errorf("failed to retrieve credentials: %w", err)
+	}
+
+	return NewClient("", "", append([]Option{WithCredentialsProvider(cache)}, opts...)...)
 }
 
-// ListProjects retrieves a list of all projects in your organization.
+// ListProjects retrieves the first page of projects in your organization.
 // Each project contains clusters, users, and other resources.
+// Use ListProjectsWithOptions to select a page or AllProjects to walk every page.
 //
 // Returns:
 //   - *models.OpenapiListProjectsResp: A list of projects with their details
 //   - error: An error if the request fails
 func (c *Client) ListProjects() (*models.OpenapiListProjectsResp, error) {
-	url := fmt.Sprintf("%s/api/%s/projects", c.baseURL, APIVersion)
+	return c.ListProjectsWithOptions(context.Background(), nil)
+}
+
+// ListProjectsWithOptions retrieves one page of projects in your organization.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - opts: The page to retrieve; nil uses the server defaults
+//
+// Returns:
+//   - *models.OpenapiListProjectsResp: A page of projects and the total count
+//   - error: An error if the request fails
+func (c *Client) ListProjectsWithOptions(ctx context.Context, opts *ListOptions) (*models.OpenapiListProjectsResp, error) {
+	url := withListOptions(fmt.Sprintf("%s/api/%s/projects", c.baseURL, c.apiVersion), opts)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.doRequest(req)
+	resp, err := c.doRequestWithRetry(ctx, OpListProjects, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -101,6 +197,19 @@ func (c *Client) ListProjects() (*models.OpenapiListProjectsResp, error) {
 	return &projects, nil
 }
 
+// AllProjects returns an iterator over every project in your organization,
+// fetching further pages as the iteration proceeds.
+// An error stops the iteration and is yielded with a nil project.
+func (c *Client) AllProjects(ctx context.Context) iter.Seq2[*models.OpenapiListProjectItem, error] {
+	return paginate(ctx, func(ctx context.Context, opts *ListOptions) ([]*models.OpenapiListProjectItem, int64, error) {
+		resp, err := c.ListProjectsWithOptions(ctx, opts)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp.Items, totalValue(resp.Total), nil
+	})
+}
+
 // CreateProject creates a new project in your organization.
 // A project is a logical container for clusters and other resources.
 //
@@ -111,11 +220,25 @@ func (c *Client) ListProjects() (*models.OpenapiListProjectsResp, error) {
 //   - *models.OpenapiCreateProjectResp: The created project details
 //   - error: An error if the request fails or validation fails
 func (c *Client) CreateProject(req *models.OpenapiCreateProjectReq) (*models.OpenapiCreateProjectResp, error) {
+	return c.CreateProjectWithContext(context.Background(), req)
+}
+
+// CreateProjectWithContext creates a new project in your organization,
+// using ctx for cancellation and deadlines.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - req: The project creation request containing the project name
+//
+// Returns:
+//   - *models.OpenapiCreateProjectResp: The created project details
+//   - error: An error if the request fails or validation fails
+func (c *Client) CreateProjectWithContext(ctx context.Context, req *models.OpenapiCreateProjectReq) (*models.OpenapiCreateProjectResp, error) {
 	if req == nil {
-		return nil, fmt.Errorf("request is required")
+		return nil, errors.Required("request")
 	}
 
-	url := fmt.Sprintf("%s/api/%s/projects", c.baseURL, APIVersion)
+	url := fmt.Sprintf("%s/api/%s/projects", c.baseURL, c.apiVersion)
 
 	reqBody, err := json.Marshal(req)
 	if err != nil {
@@ -128,7 +251,7 @@ func (c *Client) CreateProject(req *models.OpenapiCreateProjectReq) (*models.Ope
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.doRequest(httpReq)
+	resp, err := c.doRequestWithRetry(ctx, OpCreateProject, httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -146,105 +269,199 @@ func (c *Client) CreateProject(req *models.OpenapiCreateProjectReq) (*models.Ope
 	return &createResp, nil
 }
 
-func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
-	return c.doRequestWithRetry(context.Background(), req)
+// doRequestWithRetry sends req with the retry policy of op.
+func (c *Client) doRequestWithRetry(ctx context.Context, op Operation, req *http.Request) (*http.Response, error) {
+	resp, _, err := c.doRequestWithReconcile(ctx, op, req, nil)
+	return resp, err
 }
 
-func (c *Client) doRequestWithRetry(ctx context.Context, req *http.Request) (*http.Response, error) {
-	// Store request body for potential retry
+// doRequestWithReconcile sends req with the retry policy of op, retrying only
+// as far as op's Idempotency allows (see Operation.Idempotency). Before a
+// non-idempotent request is resent after a failure the server may have acted
+// on, reconcile looks for its effect; if it is found, or a delete finds the
+// resource gone, no further attempt is made and reconciled is true with a nil
+// response.
+func (c *Client) doRequestWithReconcile(ctx context.Context, op Operation, req *http.Request, reconcile reconcileFunc) (resp *http.Response, reconciled bool, err error) {
+	// Bind every attempt, including the digest handshake, to ctx
+	req = req.WithContext(ctx)
+
+	// Store request body for potential retry, unless the request can
+	// recreate it through GetBody (e.g. streamed file uploads)
 	var bodyBytes []byte
 	if req.Body != nil {
-		bodyBytes, _ = io.ReadAll(req.Body)
+		if req.GetBody == nil {
+			bodyBytes, _ = io.ReadAll(req.Body)
+		}
 		req.Body.Close()
 	}
 
+	idempotency := op.Idempotency()
 	var finalResp *http.Response
 	var finalErr error
+	// uncertain is set once an attempt failed after the server may have acted on it
+	var uncertain bool
+
+	// failed records the error of an attempt and decides whether it may be retried
+	failed := func(err error) error {
+		finalErr = err
+		if idempotency == Idempotent || !mayHaveBeenProcessed(err) {
+			return err
+		}
+		uncertain = true
+		if idempotency == NonIdempotent && reconcile == nil {
+			return retry.Permanent(err)
+		}
+		return err
+	}
 
 	operation := func() error {
+		if uncertain && reconcile != nil {
+			done, err := reconcile(ctx)
+			if err != nil {
+				// The outcome is unknown, so report the failure rather than risk a duplicate
+				return retry.Permanent(finalErr)
+			}
+			if done {
+				reconciled = true
+				return nil
+			}
+		}
+
 		// Restore request body for each attempt
-		if bodyBytes != nil {
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				finalErr = fmt.Errorf("failed to rewind request body: %w", err)
+				return finalErr
+			}
+			req.Body = body
+		} else if bodyBytes != nil {
 			req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 		}
 
 		resp, err := c.executeHTTPRequest(req)
 		if err != nil {
-			finalErr = err
-			return err
+			return failed(err)
 		}
 
 		// Check for API errors
 		if resp.StatusCode >= 400 {
 			apiErr := c.parseAPIError(resp)
-			finalErr = apiErr
 			resp.Body.Close()
-			return apiErr
+
+			// An earlier attempt of the delete went through
+			if uncertain && idempotency == IdempotentDelete && apiErr.IsNotFoundError() {
+				reconciled = true
+				return nil
+			}
+			return failed(apiErr)
 		}
 
 		finalResp = resp
 		return nil
 	}
 
-	err := c.retryExecutor.Execute(ctx, operation)
-	if err != nil {
-		return nil, finalErr
+	if err := c.retryExecutorFor(op).Execute(ctx, operation); err != nil {
+		// Report cancellation rather than the last attempt's error
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, false, ctxErr
+		}
+		// An open circuit breaker stopped the request before or between attempts
+		if stderrors.Is(err, retry.ErrCircuitOpen) {
+			return nil, false, err
+		}
+		return nil, false, finalErr
 	}
 
-	return finalResp, nil
+	return finalResp, reconciled, nil
 }
 
+// retryExecutorFor returns the executor applying the retry policy of op.
+func (c *Client) retryExecutorFor(op Operation) *retry.RetryExecutor {
+	if executor, ok := c.operationRetry[op]; ok {
+		return executor
+	}
+	return c.retryExecutor
+}
+
+// maxChallengeResends bounds how often a request is resent after a 401 challenge.
+const maxChallengeResends = 2
+
 func (c *Client) executeHTTPRequest(req *http.Request) (*http.Response, error) {
-	// Store request body before making the request
-	var bodyBytes []byte
-	if req.Body != nil {
-		bodyBytes, _ = io.ReadAll(req.Body)
+	// Make the body replayable for the authenticator and for resends after a challenge
+	if req.Body != nil && req.GetBody == nil {
+		bodyBytes, _ := io.ReadAll(req.Body)
 		req.Body.Close()
-		req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(bodyBytes)), nil
+		}
+		req.Body, _ = req.GetBody()
 	}
 
-	// First attempt without auth
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.sendAuthenticated(req)
 	if err != nil {
 		return nil, err
 	}
 
-	// If we get 401, try with digest auth
-	if resp.StatusCode == http.StatusUnauthorized {
-		authHeader := resp.Header.Get("WWW-Authenticate")
-		if authHeader != "" {
+	// If we get 401, let the authenticator answer the challenge, e.g. with a new digest nonce
+	for resends := 0; resp.StatusCode == http.StatusUnauthorized && resends < maxChallengeResends; resends++ {
+		retry, err := c.authenticator.HandleChallenge(req, resp)
+		if err != nil {
 			resp.Body.Close()
+			return nil, err
+		}
+		if !retry {
+			break
+		}
+		resp.Body.Close()
 
-			// Parse the challenge
-			if err := c.digestAuth.ParseChallenge(authHeader); err != nil {
-				return nil, fmt.Errorf("failed to parse auth challenge: %w", err)
-			}
+		// The clone's body is replaced from GetBody by sendAuthenticated
+		req = req.Clone(req.Context())
+		if resp, err = c.sendAuthenticated(req); err != nil {
+			return nil, err
+		}
+	}
 
-			// Create new request with auth header and restored body
-			var newBody io.ReadCloser
-			if bodyBytes != nil {
-				newBody = io.NopCloser(bytes.NewBuffer(bodyBytes))
-			}
+	return resp, nil
+}
 
-			newReq, err := http.NewRequest(req.Method, req.URL.String(), newBody)
-			if err != nil {
-				return nil, fmt.Errorf("failed to create auth request: %w", err)
+// sendAuthenticated adds the User-Agent and credentials to req and sends it,
+// waiting for the rate limiter first if one is configured.
+func (c *Client) sendAuthenticated(req *http.Request) (*http.Response, error) {
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.Wait(req.Context()); err != nil {
+			if req.Body != nil {
+				req.Body.Close()
 			}
+			return nil, err
+		}
+	}
 
-			// Copy headers from original request
-			for k, v := range req.Header {
-				newReq.Header[k] = v
-			}
+	req.Header.Set("User-Agent", c.userAgent)
 
-			// Add digest auth header
-			authValue := c.digestAuth.GenerateAuthHeader(c.publicKey, c.privateKey, req.Method, req.URL.Path)
-			newReq.Header.Set("Authorization", authValue)
+	if err := c.authenticator.Authenticate(req); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("failed to authenticate request: %w", err)
+	}
 
-			// Retry the request
-			return c.httpClient.Do(newReq)
+	// Signing the body may have consumed a reader shared with GetBody
+	// (e.g. a seekable upload), so start the request body afresh
+	if req.GetBody != nil && req.Body != nil {
+		req.Body.Close()
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
 		}
+		req.Body = body
 	}
 
-	return resp, nil
+	resp, err := c.httpClient.Do(req)
+	if err == nil && c.rateLimiter != nil {
+		c.rateLimiter.Observe(errors.ParseRateLimit(resp.Header, time.Now()))
+	}
+	return resp, err
 }
 
 func (c *Client) parseAPIError(resp *http.Response) errors.APIError {
@@ -254,7 +471,20 @@ func (c *Client) parseAPIError(resp *http.Response) errors.APIError {
 
 	// Try to decode error response
 	var errorResp models.ErrorResponse
-	if err := json.NewDecoder(resp.Body).Decode(&errorResp); err == nil {
+	body, err := io.ReadAll(resp.Body)
+	if err == nil {
+		if err = json.Unmarshal(body, &errorResp); err != nil {
+			// Keep the code and message even if the details are malformed
+			var status struct {
+				Code    *int64  `json:"code"`
+				Message *string `json:"message"`
+			}
+			if err = json.Unmarshal(body, &status); err == nil {
+				errorResp = models.ErrorResponse{Code: status.Code, Message: status.Message}
+			}
+		}
+	}
+	if err == nil {
 		if errorResp.Code != nil {
 			apiError.Code = *errorResp.Code
 		}
@@ -267,16 +497,8 @@ func (c *Client) parseAPIError(resp *http.Response) errors.APIError {
 		apiError.Message = http.StatusText(resp.StatusCode)
 	}
 
-	// Add rate limit information if available
-	if resp.StatusCode == http.StatusTooManyRequests {
-		if resetHeader := resp.Header.Get("X-Ratelimit-Reset"); resetHeader != "" {
-			if resetTime, err := strconv.ParseInt(resetHeader, 10, 64); err == nil {
-				apiError.Details = append(apiError.Details, map[string]interface{}{
-					"rate_limit_reset": resetTime,
-				})
-			}
-		}
-	}
+	// Keep the rate-limit headers so retries can wait for the advertised reset
+	apiError.RateLimit = errors.ParseRateLimit(resp.Header, time.Now())
 
 	return apiError
 }
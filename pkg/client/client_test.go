@@ -1,7 +1,25 @@
 package client
 
 import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/5st7/tidb-cloud-go/pkg/auth"
+	"github.com/5st7/tidb-cloud-go/pkg/errors"
+	"github.com/5st7/tidb-cloud-go/pkg/models"
+	"github.com/5st7/tidb-cloud-go/pkg/ratelimit"
+	"github.com/5st7/tidb-cloud-go/pkg/retry"
 )
 
 func TestNewClient(t *testing.T) {
@@ -67,3 +85,791 @@ func TestNewClient(t *testing.T) {
 		})
 	}
 }
+
+func TestNewClient_Options(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []Option
+		expectedErr string
+		check       func(t *testing.T, c *Client)
+	}{
+		{
+			name: "base URL trailing slash trimmed",
+			opts: []Option{WithBaseURL("https://staging.example.com/")},
+			check: func(t *testing.T, c *Client) {
+				if c.baseURL != "https://staging.example.com" {
+					t.Errorf("baseURL = %v, want https://staging.example.com", c.baseURL)
+				}
+			},
+		},
+		{
+			name:        "relative base URL",
+			opts:        []Option{WithBaseURL("api.example.com")},
+			expectedErr: `invalid base URL "api.example.com": scheme and host are required`,
+		},
+		{
+			name:        "nil HTTP client",
+			opts:        []Option{WithHTTPClient(nil)},
+			expectedErr: "HTTP client is required",
+		},
+		{
+			name:        "non-positive timeout",
+			opts:        []Option{WithTimeout(0)},
+			expectedErr: "timeout must be positive",
+		},
+		{
+			name:        "nil retry policy",
+			opts:        []Option{WithRetryPolicy(nil)},
+			expectedErr: "retry policy is required",
+		},
+		{
+			name:        "nil operation retry policy",
+			opts:        []Option{WithOperationRetryPolicy(OpGetCluster, nil)},
+			expectedErr: "retry policy is required",
+		},
+		{
+			name:        "nil circuit breaker",
+			opts:        []Option{WithCircuitBreaker(nil)},
+			expectedErr: "circuit breaker is required",
+		},
+		{
+			name:        "empty API version",
+			opts:        []Option{WithAPIVersion("")},
+			expectedErr: `invalid API version ""`,
+		},
+		{
+			name: "timeout does not modify supplied HTTP client",
+			opts: []Option{WithTimeout(5 * time.Second), WithHTTPClient(&http.Client{Timeout: time.Minute})},
+			check: func(t *testing.T, c *Client) {
+				if c.httpClient.Timeout != 5*time.Second {
+					t.Errorf("httpClient.Timeout = %v, want 5s", c.httpClient.Timeout)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient("test_public_key", "test_private_key", tt.opts...)
+
+			if tt.expectedErr != "" {
+				if err == nil {
+					t.Fatalf("Expected error %q, got nil", tt.expectedErr)
+				}
+				if err.Error() != tt.expectedErr {
+					t.Errorf("Expected error %q, got %q", tt.expectedErr, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("NewClient() unexpected error: %v", err)
+			}
+			tt.check(t, client)
+		})
+	}
+}
+
+func TestNewClient_OptionsApplyToRequests(t *testing.T) {
+	var gotPath, gotUserAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotUserAgent = r.Header.Get("User-Agent")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(&models.OpenapiListProjectsResp{})
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{Timeout: time.Minute}
+	client, err := NewClient("test_public_key", "test_private_key",
+		WithBaseURL(server.URL),
+		WithHTTPClient(httpClient),
+		WithTimeout(10*time.Second),
+		WithRetryPolicy(&retry.RetryPolicy{MaxAttempts: 0}),
+		WithUserAgent("my-app/1.0"),
+		WithAPIVersion("v1"),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+
+	if _, err := client.ListProjects(); err != nil {
+		t.Fatalf("ListProjects() unexpected error: %v", err)
+	}
+
+	if gotPath != "/api/v1/projects" {
+		t.Errorf("Expected path /api/v1/projects, got %s", gotPath)
+	}
+	if gotUserAgent != "my-app/1.0" {
+		t.Errorf("Expected User-Agent my-app/1.0, got %s", gotUserAgent)
+	}
+	if httpClient.Timeout != time.Minute {
+		t.Errorf("Supplied HTTP client was modified, timeout = %v", httpClient.Timeout)
+	}
+}
+
+func TestClient_DigestAuthNonceReuse(t *testing.T) {
+	var mu sync.Mutex
+	var challenges int
+	var authHeaders []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		authHeader := r.Header.Get("Authorization")
+		if !strings.Contains(authHeader, `nonce="nonce-1"`) {
+			challenges++
+			w.Header().Set("WWW-Authenticate", `Digest realm="tidbcloud", nonce="nonce-1", qop="auth"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		authHeaders = append(authHeaders, authHeader)
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(&models.OpenapiClusterItem{ID: stringPtr("cluster456")})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test_public_key", "test_private_key", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+
+	if _, err := client.GetCluster("project123", "cluster456"); err != nil {
+		t.Fatalf("GetCluster() unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetCluster("project123", "cluster456"); err != nil {
+				t.Errorf("GetCluster() unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if challenges != 1 {
+		t.Errorf("Expected a single 401 challenge, got %d", challenges)
+	}
+	if len(authHeaders) != 21 {
+		t.Fatalf("Expected 21 authenticated requests, got %d", len(authHeaders))
+	}
+
+	seen := make(map[string]bool)
+	for _, h := range authHeaders {
+		i := strings.Index(h, "nc=")
+		nc := h[i+3 : i+11]
+		if seen[nc] {
+			t.Errorf("nc %s used twice", nc)
+		}
+		seen[nc] = true
+	}
+}
+
+func TestClient_DigestAuthStaleNonce(t *testing.T) {
+	tests := []struct {
+		name           string
+		qop            string
+		staleResponses int
+		expectedStatus int
+	}{
+		{name: "re-authenticates once on stale nonce", qop: "auth", staleResponses: 1, expectedStatus: http.StatusOK},
+		{name: "auth-int request with body", qop: "auth-int", staleResponses: 1, expectedStatus: http.StatusOK},
+		{name: "gives up on repeated stale nonce", qop: "auth", staleResponses: 2, expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests, stale int
+			var gotBody string
+			nonce := "nonce-1"
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				challenge := `Digest realm="tidbcloud", nonce="%s", qop="` + tt.qop + `", algorithm=SHA-256`
+
+				authHeader := r.Header.Get("Authorization")
+				switch {
+				case authHeader == "":
+					w.Header().Set("WWW-Authenticate", fmt.Sprintf(challenge, nonce))
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				case stale < tt.staleResponses:
+					// Expire the nonce the request was signed with
+					stale++
+					nonce = fmt.Sprintf("nonce-%d", stale+1)
+					w.Header().Set("WWW-Authenticate", fmt.Sprintf(challenge, nonce)+", stale=true")
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				if !strings.Contains(authHeader, `nonce="`+nonce+`"`) || !strings.Contains(authHeader, "qop="+tt.qop+",") {
+					t.Errorf("Unexpected Authorization header: %s", authHeader)
+				}
+				body, _ := io.ReadAll(r.Body)
+				gotBody = string(body)
+
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			client, err := NewClient("test_public_key", "test_private_key",
+				WithBaseURL(server.URL), WithRetryPolicy(&retry.RetryPolicy{MaxAttempts: 0}))
+			if err != nil {
+				t.Fatalf("NewClient() unexpected error: %v", err)
+			}
+
+			req, err := http.NewRequest("PATCH", server.URL+"/api/v1beta/projects/p/clusters/c", strings.NewReader(`{"config":{"paused":true}}`))
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			resp, err := client.executeHTTPRequest(req)
+			if err != nil {
+				t.Fatalf("executeHTTPRequest() unexpected error: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if tt.expectedStatus == http.StatusOK && gotBody != `{"config":{"paused":true}}` {
+				t.Errorf("Unexpected request body %q", gotBody)
+			}
+			if requests != 3 {
+				t.Errorf("Expected 3 requests, got %d", requests)
+			}
+		})
+	}
+}
+
+func TestClient_WithAuthenticator(t *testing.T) {
+	tests := []struct {
+		name           string
+		authenticator  auth.Authenticator
+		expectedHeader string
+		expectedValue  string
+	}{
+		{
+			name:           "bearer token",
+			authenticator:  mustBearer(t, auth.StaticToken("abc123")),
+			expectedHeader: "Authorization",
+			expectedValue:  "Bearer abc123",
+		},
+		{
+			name: "custom signer",
+			authenticator: auth.AuthenticatorFunc(func(req *http.Request) error {
+				req.Header.Set("X-Gateway-Signature", req.Method+" "+req.URL.Path)
+				return nil
+			}),
+			expectedHeader: "X-Gateway-Signature",
+			expectedValue:  "GET /api/v1beta/projects",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if got := r.Header.Get(tt.expectedHeader); got != tt.expectedValue {
+					t.Errorf("%s = %q, want %q", tt.expectedHeader, got, tt.expectedValue)
+				}
+				w.WriteHeader(http.StatusOK)
+				json.NewEncoder(w).Encode(&models.OpenapiListProjectsResp{})
+			}))
+			defer server.Close()
+
+			// The key pair is not needed with a custom authenticator
+			client, err := NewClient("", "", WithBaseURL(server.URL), WithAuthenticator(tt.authenticator))
+			if err != nil {
+				t.Fatalf("NewClient() unexpected error: %v", err)
+			}
+
+			if _, err := client.ListProjects(); err != nil {
+				t.Fatalf("ListProjects() unexpected error: %v", err)
+			}
+			if requests != 1 {
+				t.Errorf("Expected 1 request, got %d", requests)
+			}
+		})
+	}
+
+	if _, err := NewClient("", "", WithAuthenticator(nil)); err == nil || err.Error() != "authenticator is required" {
+		t.Errorf("Expected authenticator is required error, got %v", err)
+	}
+}
+
+func mustBearer(t *testing.T, source auth.TokenSource) auth.Authenticator {
+	t.Helper()
+	a, err := auth.NewBearerAuthenticator(source)
+	if err != nil {
+		t.Fatalf("NewBearerAuthenticator() unexpected error: %v", err)
+	}
+	return a
+}
+
+func TestNewClientFromProfile(t *testing.T) {
+	for _, name := range []string{auth.EnvPublicKey, auth.EnvPrivateKey, auth.EnvProfile, "TIDB_CLOUD_PUBLIC_KEY", "TIDB_CLOUD_PRIVATE_KEY"} {
+		t.Setenv(name, "")
+	}
+
+	path := filepath.Join(t.TempDir(), "credentials")
+	content := "[default]\npublic_key = default-public\nprivate_key = default-private\n\n[staging]\npublic_key = staging-public\nprivate_key = staging-private\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write credentials file: %v", err)
+	}
+	t.Setenv(auth.EnvCredentialsFile, path)
+
+	tests := []struct {
+		name          string
+		profile       string
+		expectedError bool
+	}{
+		{name: "default chain", profile: ""},
+		{name: "named profile", profile: "staging"},
+		{name: "unknown profile", profile: "prod", expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClientFromProfile(tt.profile, WithBaseURL("https://staging.example.com"))
+
+			if tt.expectedError {
+				if err == nil {
+					t.Fatal("NewClientFromProfile() expected error but got none")
+				}
+				if !strings.Contains(err.Error(), auth.ErrNoCredentials.Error()) {
+					t.Errorf("NewClientFromProfile() error = %v, want no credentials error", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("NewClientFromProfile() unexpected error: %v", err)
+			}
+			if client.baseURL != "https://staging.example.com" {
+				t.Errorf("NewClientFromProfile() baseURL = %v, options not applied", client.baseURL)
+			}
+		})
+	}
+}
+
+func TestClient_WithCredentialsProvider(t *testing.T) {
+	var mu sync.Mutex
+	validKey := "key-1"
+	var usernames []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		authHeader := r.Header.Get("Authorization")
+		if !strings.Contains(authHeader, fmt.Sprintf(`username="%s"`, validKey)) {
+			w.Header().Set("WWW-Authenticate", `Digest realm="tidbcloud", nonce="nonce-1", qop="auth"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		usernames = append(usernames, validKey)
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(&models.OpenapiListProjectsResp{})
+	}))
+	defer server.Close()
+
+	var current auth.Credentials
+	setKey := func(key string) {
+		mu.Lock()
+		defer mu.Unlock()
+		validKey = key
+		current = auth.Credentials{PublicKey: key, PrivateKey: key + "-secret"}
+	}
+	setKey("key-1")
+
+	provider := credentialsFunc(func(ctx context.Context) (auth.Credentials, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return current, nil
+	})
+
+	client, err := NewClient("", "", WithBaseURL(server.URL), WithCredentialsProvider(provider))
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+
+	if _, err := client.ListProjects(); err != nil {
+		t.Fatalf("ListProjects() unexpected error: %v", err)
+	}
+
+	// The server rejects the old key: the cache is invalidated and the request
+	// is resent with the rotated key
+	setKey("key-2")
+	if _, err := client.ListProjects(); err != nil {
+		t.Fatalf("ListProjects() after rotation unexpected error: %v", err)
+	}
+
+	if len(usernames) != 2 || usernames[1] != "key-2" {
+		t.Errorf("Expected requests authenticated with key-1 then key-2, got %v", usernames)
+	}
+
+	if _, err := NewClient("", "", WithCredentialsProvider(nil)); err == nil || err.Error() != "credentials provider is required" {
+		t.Errorf("Expected credentials provider is required error, got %v", err)
+	}
+}
+
+// credentialsFunc adapts a function to auth.CredentialsProvider.
+type credentialsFunc func(ctx context.Context) (auth.Credentials, error)
+
+func (f credentialsFunc) Retrieve(ctx context.Context) (auth.Credentials, error) {
+	return f(ctx)
+}
+
+func TestClient_RateLimitHeaders(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("X-Ratelimit-Limit-Minute", "100")
+			w.Header().Set("X-Ratelimit-Remaining-Minute", "0")
+			w.Header().Set("X-Ratelimit-Reset", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": 49900007, "message": "rate limited"})
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(&models.OpenapiListProjectsResp{})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test_public_key", "test_private_key", WithBaseURL(server.URL), WithAuthenticator(mustBearer(t, auth.StaticToken("abc123"))))
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+
+	// The limit has already reset, so the retry is sent without backing off
+	start := time.Now()
+	if _, err := client.ListProjects(); err != nil {
+		t.Fatalf("ListProjects() unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the retry to honor X-Ratelimit-Reset, took %v", elapsed)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(`{"code": 49900007}`))}
+	resp.Header.Set("X-Ratelimit-Limit-Minute", "100")
+	resp.Header.Set("X-Ratelimit-Remaining-Minute", "0")
+	resp.Header.Set("X-Ratelimit-Reset", "23")
+	apiErr := client.parseAPIError(resp)
+	if apiErr.RateLimit == nil || apiErr.RateLimit.Limit != 100 || apiErr.RateLimit.Remaining != 0 {
+		t.Fatalf("parseAPIError() RateLimit = %+v, want limit 100 and remaining 0", apiErr.RateLimit)
+	}
+	if delay, ok := apiErr.RetryDelay(); !ok || delay < 22*time.Second || delay > 23*time.Second {
+		t.Errorf("RetryDelay() = %v, %v, want about 23s", delay, ok)
+	}
+	if len(apiErr.Details) != 0 {
+		t.Errorf("Expected rate-limit headers to stay out of Details, got %v", apiErr.Details)
+	}
+}
+
+func TestClient_WithRateLimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Another process using the same key has consumed most of the quota
+		w.Header().Set("X-Ratelimit-Limit-Minute", "100")
+		w.Header().Set("X-Ratelimit-Remaining-Minute", "0")
+		w.Header().Set("X-Ratelimit-Reset", "60")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(&models.OpenapiListProjectsResp{})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test_public_key", "test_private_key",
+		WithBaseURL(server.URL),
+		WithAuthenticator(mustBearer(t, auth.StaticToken("abc123"))),
+		WithRateLimiter(ratelimit.NewDefaultLimiter()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+
+	if _, err := client.ListProjects(); err != nil {
+		t.Fatalf("ListProjects() unexpected error: %v", err)
+	}
+
+	// The limiter waits for the reset instead of sending a request bound to fail
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.ListProjectsWithOptions(ctx, nil); err == nil || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+		t.Errorf("ListProjectsWithOptions() error = %v, want deadline exceeded", err)
+	}
+
+	if _, err := NewClient("", "", WithRateLimiter(nil)); err == nil || err.Error() != "rate limiter is required" {
+		t.Errorf("Expected rate limiter is required error, got %v", err)
+	}
+}
+
+func TestClient_WithOperationRetryPolicy(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.Method]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	fast := &retry.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Backoff: retry.ConstantBackoff}
+	client, err := NewClient("test_public_key", "test_private_key",
+		WithBaseURL(server.URL),
+		WithAuthenticator(mustBearer(t, auth.StaticToken("abc123"))),
+		WithRetryPolicy(fast),
+		WithOperationRetryPolicy(OpGetCluster, &retry.RetryPolicy{MaxAttempts: 4, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+		WithOperationRetryPolicy(OpCreateCluster, &retry.RetryPolicy{MaxAttempts: 0}),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+
+	if _, err := client.GetCluster("project123", "cluster456"); err == nil {
+		t.Fatal("GetCluster() expected error but got none")
+	}
+	if _, err := client.CreateCluster("project123", &models.OpenapiCreateClusterReq{}); err == nil {
+		t.Fatal("CreateCluster() expected error but got none")
+	}
+	if err := client.DeleteCluster("project123", "cluster456"); err == nil {
+		t.Fatal("DeleteCluster() expected error but got none")
+	}
+
+	expected := map[string]int{"GET": 5, "POST": 1, "DELETE": 3}
+	for method, want := range expected {
+		if requests[method] != want {
+			t.Errorf("Expected %d %s requests, got %d", want, method, requests[method])
+		}
+	}
+}
+
+func TestClient_WithCircuitBreaker(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	breaker := retry.NewCircuitBreaker(&retry.CircuitBreakerPolicy{FailureThreshold: 2, OpenTimeout: time.Minute})
+	newClient := func() *Client {
+		client, err := NewClient("", "",
+			WithBaseURL(server.URL),
+			WithAuthenticator(mustBearer(t, auth.StaticToken("abc123"))),
+			WithCircuitBreaker(breaker),
+			WithRetryPolicy(&retry.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+		)
+		if err != nil {
+			t.Fatalf("NewClient() unexpected error: %v", err)
+		}
+		return client
+	}
+
+	if _, err := newClient().ListProjects(); !stderrors.Is(err, retry.ErrCircuitOpen) {
+		t.Errorf("ListProjects() error = %v, want ErrCircuitOpen", err)
+	}
+
+	// A second client sharing the breaker fails fast
+	if _, err := newClient().GetCluster("project123", "cluster456"); !stderrors.Is(err, retry.ErrCircuitOpen) {
+		t.Errorf("GetCluster() error = %v, want ErrCircuitOpen", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests before the breaker opened, got %d", requests)
+	}
+	if state := breaker.State(); state != retry.CircuitOpen {
+		t.Errorf("Expected breaker state open, got %v", state)
+	}
+}
+
+func TestClient_SentinelErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 49900002, "message": "cluster not found"})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("", "", WithBaseURL(server.URL), WithAuthenticator(mustBearer(t, auth.StaticToken("abc123"))))
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+
+	_, err = client.GetCluster("project123", "cluster456")
+	if !stderrors.Is(err, errors.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if stderrors.Is(err, errors.ErrInvalidArgument) {
+		t.Errorf("Expected 404 not to match ErrInvalidArgument, got %v", err)
+	}
+
+	_, err = client.GetCluster("project123", "")
+	var validationErr *errors.ValidationError
+	if !stderrors.As(err, &validationErr) || validationErr.Field != "cluster ID" {
+		t.Errorf("Expected ValidationError for cluster ID, got %v", err)
+	}
+	if !stderrors.Is(err, errors.ErrInvalidArgument) {
+		t.Errorf("Expected ErrInvalidArgument, got %v", err)
+	}
+
+	if _, err := NewClient("", "", WithTimeout(0)); !stderrors.Is(err, errors.ErrInvalidArgument) {
+		t.Errorf("Expected ErrInvalidArgument for invalid option, got %v", err)
+	}
+}
+
+func TestClient_ErrorDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"code": 49900001, "message": "cluster quota exceeded", "details": [
+			{"@type": "type.googleapis.com/google.rpc.QuotaFailure", "violations": [{"subject": "project:project123", "description": "at most 10 clusters"}]}
+		]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("", "", WithBaseURL(server.URL), WithAuthenticator(mustBearer(t, auth.StaticToken("abc123"))))
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+
+	_, err = client.CreateCluster("project123", &models.OpenapiCreateClusterReq{Name: stringPtr("cluster0")})
+	var apiErr errors.APIError
+	if !stderrors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError, got %v", err)
+	}
+	if !apiErr.IsQuotaExceeded() {
+		t.Errorf("Expected quota exceeded, got category %s", apiErr.Category())
+	}
+
+	var quota errors.QuotaFailure
+	if !apiErr.Detail(errors.TypeQuotaFailure, &quota) || len(quota.Violations) != 1 {
+		t.Fatalf("Expected one quota violation, got %+v", quota)
+	}
+	if quota.Violations[0].Description != "at most 10 clusters" {
+		t.Errorf("Unexpected violation %+v", quota.Violations[0])
+	}
+}
+
+func TestClient_ErrorDetails_Lenient(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            string
+		expectedDetails []string
+	}{
+		{
+			name:            "string details",
+			body:            `{"code":49900001,"message":"cluster name invalid","details":["name must be 4-64 chars"]}`,
+			expectedDetails: []string{"name must be 4-64 chars"},
+		},
+		{
+			name:            "mixed details",
+			body:            `{"code":49900001,"message":"cluster name invalid","details":["name must be 4-64 chars", {"@type": 7, "field": "name"}, 42]}`,
+			expectedDetails: []string{"name must be 4-64 chars", "", "42"},
+		},
+		{
+			name: "details that are not an array",
+			body: `{"code":49900001,"message":"cluster name invalid","details":{"field":"name"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client, err := NewClient("", "", WithBaseURL(server.URL), WithAuthenticator(mustBearer(t, auth.StaticToken("abc123"))))
+			if err != nil {
+				t.Fatalf("NewClient() unexpected error: %v", err)
+			}
+
+			_, err = client.CreateCluster("project123", &models.OpenapiCreateClusterReq{Name: stringPtr("c")})
+			var apiErr errors.APIError
+			if !stderrors.As(err, &apiErr) {
+				t.Fatalf("Expected APIError, got %v", err)
+			}
+			if expected := "TiDB Cloud API error (400): cluster name invalid (code: 49900001)"; apiErr.Error() != expected {
+				t.Errorf("Expected error %q, got %q", expected, apiErr.Error())
+			}
+
+			if len(apiErr.Details) != len(tt.expectedDetails) {
+				t.Fatalf("Expected %d details, got %d", len(tt.expectedDetails), len(apiErr.Details))
+			}
+			for i, detail := range apiErr.Details {
+				if detail.Message != tt.expectedDetails[i] {
+					t.Errorf("Expected detail %d message %q, got %q", i, tt.expectedDetails[i], detail.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_ErrorCategories(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected func(errors.APIError) bool
+	}{
+		{
+			name:   "quota exceeded",
+			status: http.StatusBadRequest,
+			body: `{"code": 49900001, "message": "the number of clusters in project project123 exceeds the quota", "details": [
+				{"@type": "type.googleapis.com/google.rpc.QuotaFailure", "violations": [{"subject": "project:project123", "description": "at most 10 clusters"}]}
+			]}`,
+			expected: errors.APIError.IsQuotaExceeded,
+		},
+		{
+			name:   "invalid state",
+			status: http.StatusBadRequest,
+			body: `{"code": 49900001, "message": "cluster cluster456 is not available", "details": [
+				{"@type": "type.googleapis.com/google.rpc.PreconditionFailure", "violations": [{"type": "STATE", "subject": "cluster:cluster456", "description": "cluster is CREATING"}]}
+			]}`,
+			expected: errors.APIError.IsInvalidState,
+		},
+		{
+			name:     "conflict",
+			status:   http.StatusConflict,
+			body:     `{"code": 49900001, "message": "cluster name Cluster0 already exists", "details": []}`,
+			expected: errors.APIError.IsConflict,
+		},
+		{
+			name:     "conflict with string details",
+			status:   http.StatusConflict,
+			body:     `{"code": 49900001, "message": "cluster name Cluster0 already exists", "details": ["choose another name"]}`,
+			expected: errors.APIError.IsConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client, err := NewClient("", "", WithBaseURL(server.URL), WithAuthenticator(mustBearer(t, auth.StaticToken("abc123"))))
+			if err != nil {
+				t.Fatalf("NewClient() unexpected error: %v", err)
+			}
+
+			_, err = client.CreateCluster("project123", &models.OpenapiCreateClusterReq{Name: stringPtr("Cluster0")})
+			var apiErr errors.APIError
+			if !stderrors.As(err, &apiErr) {
+				t.Fatalf("Expected APIError, got %v", err)
+			}
+			if !tt.expected(apiErr) {
+				t.Errorf("Unexpected category %s for %s", apiErr.Category(), tt.body)
+			}
+		})
+	}
+}
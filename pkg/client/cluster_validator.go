@@ -0,0 +1,375 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/5st7/tidb-cloud-go/pkg/errors"
+	"github.com/5st7/tidb-cloud-go/pkg/models"
+)
+
+// DefaultProfileCacheTTL is how long a ClusterValidator reuses provider region
+// profiles before fetching them again.
+const DefaultProfileCacheTTL = time.Hour
+
+// FieldViolation describes a single invalid field of a cluster request.
+// Field is the JSON path of the field, e.g. "config.components.tikv.node_size".
+type FieldViolation struct {
+	Field   string
+	Message string
+}
+
+// ClusterValidationError is returned by ClusterValidator when a cluster request
+// does not match the profiles offered for its cloud provider and region.
+type ClusterValidationError struct {
+	Violations []FieldViolation
+}
+
+// Error implements the error interface and lists every violation.
+func (e *ClusterValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", v.Field, v.Message))
+	}
+	return fmt.Sprintf("invalid cluster request: %s", strings.Join(msgs, "; "))
+}
+
+// Is reports whether target is errors.ErrInvalidArgument.
+func (e *ClusterValidationError) Is(target error) bool {
+	return target == errors.ErrInvalidArgument
+}
+
+// ClusterValidator checks CreateCluster and UpdateCluster requests against the
+// node sizes, node quantity ranges and storage ranges returned by
+// ListProviderRegions, so that invalid requests are rejected before anything
+// is sent to the server. Profiles are fetched on first use and cached.
+// A ClusterValidator is safe for concurrent use.
+type ClusterValidator struct {
+	client *Client
+	ttl    time.Duration
+
+	mu        sync.Mutex
+	regions   *ProviderRegions
+	fetchedAt time.Time
+	fetch     *profileFetch // In-flight fetch shared by concurrent validations
+
+	// waitHook, if set, is called by every caller about to wait for a fetch;
+	// tests use it to know when concurrent callers share the fetch
+	waitHook func()
+}
+
+// profileFetch is a fetch of the provider region profiles; done is closed
+// once regions or err is set.
+type profileFetch struct {
+	done    chan struct{}
+	regions *ProviderRegions
+	err     error
+}
+
+// NewClusterValidator creates a validator that loads profiles through the client.
+// A ttl of zero or less uses DefaultProfileCacheTTL.
+func NewClusterValidator(client *Client, ttl time.Duration) *ClusterValidator {
+	if ttl <= 0 {
+		ttl = DefaultProfileCacheTTL
+	}
+	return &ClusterValidator{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// Invalidate drops the cached profiles so the next validation fetches them
+// again. A fetch in flight is not cached when it completes.
+func (v *ClusterValidator) Invalidate() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.regions = nil
+	v.fetch = nil
+}
+
+// providerRegions returns the cached profiles, fetching them if they are
+// missing or stale. Concurrent callers share a single fetch, which runs
+// outside the lock; each caller stops waiting for it when its ctx is done.
+func (v *ClusterValidator) providerRegions(ctx context.Context) (*ProviderRegions, error) {
+	v.mu.Lock()
+	if v.regions != nil && time.Since(v.fetchedAt) < v.ttl {
+		regions := v.regions
+		v.mu.Unlock()
+		return regions, nil
+	}
+	f := v.fetch
+	if f == nil {
+		f = &profileFetch{done: make(chan struct{})}
+		v.fetch = f
+		// The fetch outlives a caller that gives up, so that the others get its result
+		go v.load(context.WithoutCancel(ctx), f)
+	}
+	hook := v.waitHook
+	v.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+
+	select {
+	case <-f.done:
+		return f.regions, f.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("failed to load provider regions: %w", ctx.Err())
+	}
+}
+
+// load fetches the profiles for f and caches them unless f was superseded,
+// e.g. by Invalidate, while it ran. A failed fetch is not cached, so the next
+// validation tries again.
+func (v *ClusterValidator) load(ctx context.Context, f *profileFetch) {
+	regions, err := v.client.LoadProviderRegionsWithContext(ctx)
+
+	v.mu.Lock()
+	if v.fetch == f {
+		v.fetch = nil
+		if err == nil {
+			v.regions = regions
+			v.fetchedAt = time.Now()
+		}
+	}
+	v.mu.Unlock()
+
+	if err != nil {
+		f.err = fmt.Errorf("failed to load provider regions: %w", err)
+	} else {
+		f.regions = regions
+	}
+	close(f.done)
+}
+
+// ValidateCreateCluster checks a cluster creation request.
+// It returns a *ClusterValidationError listing every violation, or an error if
+// the provider region profiles cannot be loaded.
+func (v *ClusterValidator) ValidateCreateCluster(req *models.OpenapiCreateClusterReq) error {
+	return v.ValidateCreateClusterWithContext(context.Background(), req)
+}
+
+// ValidateCreateClusterWithContext is ValidateCreateCluster with ctx bounding
+// the provider region lookup.
+func (v *ClusterValidator) ValidateCreateClusterWithContext(ctx context.Context, req *models.OpenapiCreateClusterReq) error {
+	if req == nil {
+		return errors.Required("request")
+	}
+
+	var violations []FieldViolation
+	required := []struct {
+		field string
+		value *string
+	}{
+		{"name", req.Name},
+		{"cluster_type", req.ClusterType},
+		{"cloud_provider", req.CloudProvider},
+		{"region", req.Region},
+	}
+	for _, r := range required {
+		if stringValue(r.value) == "" {
+			violations = append(violations, FieldViolation{Field: r.field, Message: "is required"})
+		}
+	}
+	if len(violations) > 0 {
+		return &ClusterValidationError{Violations: violations}
+	}
+
+	regions, err := v.providerRegions(ctx)
+	if err != nil {
+		return err
+	}
+
+	cloudProvider, region, clusterType := *req.CloudProvider, *req.Region, *req.ClusterType
+	if regions.Find(cloudProvider, region, clusterType) == nil {
+		return &ClusterValidationError{Violations: []FieldViolation{{
+			Field:   "region",
+			Message: fmt.Sprintf("%s clusters are not offered in %s %s", clusterType, cloudProvider, region),
+		}}}
+	}
+
+	var components *models.OpenapiClusterComponents
+	if req.Config != nil {
+		components = req.Config.Components
+	}
+
+	if components == nil {
+		if strings.EqualFold(clusterType, "DEDICATED") {
+			violations = append(violations, FieldViolation{Field: "config.components", Message: "is required for DEDICATED clusters"})
+		}
+		return newClusterValidationError(violations)
+	}
+
+	check := componentChecker{regions: regions, cloudProvider: cloudProvider, region: region, clusterType: clusterType}
+
+	if components.TiDB == nil {
+		violations = append(violations, FieldViolation{Field: "config.components.tidb", Message: "is required"})
+	} else {
+		violations = append(violations, check.validate(ComponentTiDB, components.TiDB.NodeSize, components.TiDB.NodeQuantity, nil)...)
+	}
+	if components.TiKV == nil {
+		violations = append(violations, FieldViolation{Field: "config.components.tikv", Message: "is required"})
+	} else {
+		violations = append(violations, check.validate(ComponentTiKV, components.TiKV.NodeSize, components.TiKV.NodeQuantity, components.TiKV.StorageSizeGib)...)
+	}
+	if components.TiFlash != nil {
+		violations = append(violations, check.validate(ComponentTiFlash, components.TiFlash.NodeSize, components.TiFlash.NodeQuantity, components.TiFlash.StorageSizeGib)...)
+	}
+
+	return newClusterValidationError(violations)
+}
+
+// ValidateUpdateCluster checks a cluster update request.
+// The current cluster is fetched to resolve its cloud provider, region and any
+// node size the request leaves unchanged. It returns a *ClusterValidationError
+// listing every violation, or an error if the cluster or profiles cannot be loaded.
+func (v *ClusterValidator) ValidateUpdateCluster(projectID, clusterID string, req *models.OpenapiUpdateClusterReq) error {
+	return v.ValidateUpdateClusterWithContext(context.Background(), projectID, clusterID, req)
+}
+
+// ValidateUpdateClusterWithContext is ValidateUpdateCluster with ctx bounding
+// the cluster and provider region lookups.
+func (v *ClusterValidator) ValidateUpdateClusterWithContext(ctx context.Context, projectID, clusterID string, req *models.OpenapiUpdateClusterReq) error {
+	if req == nil {
+		return errors.Required("request")
+	}
+	if req.Config == nil || req.Config.Components == nil {
+		return nil
+	}
+
+	cluster, err := v.client.GetClusterWithContext(ctx, projectID, clusterID)
+	if err != nil {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	return v.validateUpdate(ctx, cluster, req.Config.Components)
+}
+
+func (v *ClusterValidator) validateUpdate(ctx context.Context, cluster *models.OpenapiClusterItem, components *models.OpenapiUpdateClusterComponents) error {
+	regions, err := v.providerRegions(ctx)
+	if err != nil {
+		return err
+	}
+
+	var current *models.OpenapiClusterComponents
+	if cluster.Config != nil {
+		current = cluster.Config.Components
+	}
+	if current == nil {
+		current = &models.OpenapiClusterComponents{}
+	}
+
+	check := componentChecker{
+		regions:       regions,
+		cloudProvider: stringValue(cluster.CloudProvider),
+		region:        stringValue(cluster.Region),
+		clusterType:   stringValue(cluster.ClusterType),
+	}
+
+	var violations []FieldViolation
+	if c := components.TiDB; c != nil {
+		var currentSize *string
+		if current.TiDB != nil {
+			currentSize = current.TiDB.NodeSize
+		}
+		violations = append(violations, check.validate(ComponentTiDB, firstString(c.NodeSize, currentSize), c.NodeQuantity, nil)...)
+	}
+	if c := components.TiKV; c != nil {
+		var currentSize *string
+		if current.TiKV != nil {
+			currentSize = current.TiKV.NodeSize
+		}
+		violations = append(violations, check.validate(ComponentTiKV, firstString(c.NodeSize, currentSize), c.NodeQuantity, c.StorageSizeGib)...)
+	}
+	if c := components.TiFlash; c != nil {
+		var currentSize *string
+		if current.TiFlash != nil {
+			currentSize = current.TiFlash.NodeSize
+		}
+		violations = append(violations, check.validate(ComponentTiFlash, firstString(c.NodeSize, currentSize), c.NodeQuantity, c.StorageSizeGib)...)
+	}
+
+	return newClusterValidationError(violations)
+}
+
+type componentChecker struct {
+	regions       *ProviderRegions
+	cloudProvider string
+	region        string
+	clusterType   string
+}
+
+// validate checks one component against its profile. A missing node size is
+// reported as a violation; nil quantity or storage values are not checked.
+func (c componentChecker) validate(component Component, nodeSize *string, nodeQuantity, storageSizeGib *int64) []FieldViolation {
+	prefix := "config.components." + string(component)
+
+	if stringValue(nodeSize) == "" {
+		return []FieldViolation{{Field: prefix + ".node_size", Message: "is required"}}
+	}
+
+	profile, ok := c.regions.Profile(c.cloudProvider, c.region, c.clusterType, component, *nodeSize)
+	if !ok {
+		return []FieldViolation{{
+			Field: prefix + ".node_size",
+			Message: fmt.Sprintf("%s is not offered in %s %s, valid sizes: %s", *nodeSize, c.cloudProvider, c.region,
+				strings.Join(c.regions.NodeSizes(c.cloudProvider, c.region, c.clusterType, component), ", ")),
+		}}
+	}
+
+	var violations []FieldViolation
+	if nodeQuantity != nil {
+		quantity := *nodeQuantity
+		switch {
+		case quantity < profile.MinNodeQuantity:
+			violations = append(violations, FieldViolation{
+				Field:   prefix + ".node_quantity",
+				Message: fmt.Sprintf("%d is below the minimum of %d", quantity, profile.MinNodeQuantity),
+			})
+		case profile.NodeQuantityStep > 0 && (quantity-profile.MinNodeQuantity)%profile.NodeQuantityStep != 0:
+			violations = append(violations, FieldViolation{
+				Field: prefix + ".node_quantity",
+				Message: fmt.Sprintf("%d must be %d plus a multiple of %d", quantity,
+					profile.MinNodeQuantity, profile.NodeQuantityStep),
+			})
+		}
+	}
+
+	if storageSizeGib != nil && profile.HasStorage() {
+		storage := *storageSizeGib
+		switch {
+		case profile.MinStorageSizeGib > 0 && storage < profile.MinStorageSizeGib:
+			violations = append(violations, FieldViolation{
+				Field:   prefix + ".storage_size_gib",
+				Message: fmt.Sprintf("%d is below the minimum of %d GiB", storage, profile.MinStorageSizeGib),
+			})
+		case profile.MaxStorageSizeGib > 0 && storage > profile.MaxStorageSizeGib:
+			violations = append(violations, FieldViolation{
+				Field:   prefix + ".storage_size_gib",
+				Message: fmt.Sprintf("%d is above the maximum of %d GiB", storage, profile.MaxStorageSizeGib),
+			})
+		}
+	}
+
+	return violations
+}
+
+func newClusterValidationError(violations []FieldViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ClusterValidationError{Violations: violations}
+}
+
+func firstString(values ...*string) *string {
+	for _, v := range values {
+		if v != nil && *v != "" {
+			return v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/5st7/tidb-cloud-go/pkg/models"
+)
+
+func newValidatorTestServer(t *testing.T, regionCalls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1beta/clusters/provider/regions":
+			atomic.AddInt32(regionCalls, 1)
+			json.NewEncoder(w).Encode(&models.OpenapiListProviderRegionsResp{Items: testProviderRegions().items})
+		case "/api/v1beta/projects/project123/clusters/cluster456":
+			json.NewEncoder(w).Encode(&models.OpenapiClusterItem{
+				ID:            stringPtr("cluster456"),
+				ClusterType:   stringPtr("DEDICATED"),
+				CloudProvider: stringPtr("AWS"),
+				Region:        stringPtr("us-west-2"),
+				Config: &models.OpenapiGetClusterConfig{
+					Components: &models.OpenapiClusterComponents{
+						TiDB: &models.OpenapiTiDBComponent{NodeSize: stringPtr("8C16G"), NodeQuantity: int64Ptr(2)},
+						TiKV: &models.OpenapiTiKVComponent{NodeSize: stringPtr("8C32G"), NodeQuantity: int64Ptr(3), StorageSizeGib: int64Ptr(500)},
+					},
+				},
+			})
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func validCreateClusterReq() *models.OpenapiCreateClusterReq {
+	return &models.OpenapiCreateClusterReq{
+		Name:          stringPtr("my-cluster"),
+		ClusterType:   stringPtr("DEDICATED"),
+		CloudProvider: stringPtr("AWS"),
+		Region:        stringPtr("us-west-2"),
+		Config: &models.OpenapiClusterConfig{
+			Components: &models.OpenapiClusterComponents{
+				TiDB: &models.OpenapiTiDBComponent{NodeSize: stringPtr("8C16G"), NodeQuantity: int64Ptr(2)},
+				TiKV: &models.OpenapiTiKVComponent{NodeSize: stringPtr("8C32G"), NodeQuantity: int64Ptr(6), StorageSizeGib: int64Ptr(1000)},
+			},
+		},
+	}
+}
+
+func TestClusterValidator_ValidateCreateCluster(t *testing.T) {
+	tests := []struct {
+		name           string
+		modify         func(req *models.OpenapiCreateClusterReq)
+		expectedFields []string
+	}{
+		{
+			name:   "valid request",
+			modify: func(req *models.OpenapiCreateClusterReq) {},
+		},
+		{
+			name: "missing region",
+			modify: func(req *models.OpenapiCreateClusterReq) {
+				req.Region = nil
+			},
+			expectedFields: []string{"region"},
+		},
+		{
+			name: "region not offered",
+			modify: func(req *models.OpenapiCreateClusterReq) {
+				req.Region = stringPtr("eu-west-1")
+			},
+			expectedFields: []string{"region"},
+		},
+		{
+			name: "invalid TiKV node size",
+			modify: func(req *models.OpenapiCreateClusterReq) {
+				req.Config.Components.TiKV.NodeSize = stringPtr("4C16G")
+			},
+			expectedFields: []string{"config.components.tikv.node_size"},
+		},
+		{
+			name: "quantity and storage violations",
+			modify: func(req *models.OpenapiCreateClusterReq) {
+				req.Config.Components.TiDB.NodeQuantity = int64Ptr(0)
+				req.Config.Components.TiKV.NodeQuantity = int64Ptr(4)
+				req.Config.Components.TiKV.StorageSizeGib = int64Ptr(8192)
+			},
+			expectedFields: []string{
+				"config.components.tidb.node_quantity",
+				"config.components.tikv.node_quantity",
+				"config.components.tikv.storage_size_gib",
+			},
+		},
+		{
+			name: "missing components for dedicated cluster",
+			modify: func(req *models.OpenapiCreateClusterReq) {
+				req.Config.Components = nil
+			},
+			expectedFields: []string{"config.components"},
+		},
+	}
+
+	var regionCalls int32
+	server := newValidatorTestServer(t, &regionCalls)
+	defer server.Close()
+
+	client, err := NewClient("test_public", "test_private")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.baseURL = server.URL
+
+	validator := NewClusterValidator(client, 0)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validCreateClusterReq()
+			tt.modify(req)
+
+			err := validator.ValidateCreateCluster(req)
+			assertViolations(t, err, tt.expectedFields)
+		})
+	}
+
+	if regionCalls != 1 {
+		t.Errorf("Expected provider regions to be fetched once, got %d", regionCalls)
+	}
+
+	validator.Invalidate()
+	if err := validator.ValidateCreateCluster(validCreateClusterReq()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if regionCalls != 2 {
+		t.Errorf("Expected provider regions to be fetched again after Invalidate, got %d", regionCalls)
+	}
+}
+
+func TestClusterValidator_ValidateUpdateCluster(t *testing.T) {
+	tests := []struct {
+		name           string
+		req            *models.OpenapiUpdateClusterReq
+		expectedFields []string
+	}{
+		{
+			name: "scale TiKV with current node size",
+			req: &models.OpenapiUpdateClusterReq{
+				Config: &models.OpenapiUpdateClusterConfig{
+					Components: &models.OpenapiUpdateClusterComponents{
+						TiKV: &models.OpenapiUpdateTiKVComponent{NodeQuantity: int64Ptr(9)},
+					},
+				},
+			},
+		},
+		{
+			name: "TiKV quantity off step",
+			req: &models.OpenapiUpdateClusterReq{
+				Config: &models.OpenapiUpdateClusterConfig{
+					Components: &models.OpenapiUpdateClusterComponents{
+						TiKV: &models.OpenapiUpdateTiKVComponent{NodeQuantity: int64Ptr(5)},
+					},
+				},
+			},
+			expectedFields: []string{"config.components.tikv.node_quantity"},
+		},
+		{
+			name: "TiDB node size not offered",
+			req: &models.OpenapiUpdateClusterReq{
+				Config: &models.OpenapiUpdateClusterConfig{
+					Components: &models.OpenapiUpdateClusterComponents{
+						TiDB: &models.OpenapiUpdateTiDBComponent{NodeSize: stringPtr("32C64G")},
+					},
+				},
+			},
+			expectedFields: []string{"config.components.tidb.node_size"},
+		},
+		{
+			name: "pause only",
+			req: &models.OpenapiUpdateClusterReq{
+				Config: &models.OpenapiUpdateClusterConfig{Paused: boolPtr(true)},
+			},
+		},
+	}
+
+	var regionCalls int32
+	server := newValidatorTestServer(t, &regionCalls)
+	defer server.Close()
+
+	client, err := NewClient("test_public", "test_private")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.baseURL = server.URL
+
+	validator := NewClusterValidator(client, 0)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateUpdateCluster("project123", "cluster456", tt.req)
+			assertViolations(t, err, tt.expectedFields)
+		})
+	}
+}
+
+func assertViolations(t *testing.T, err error, expectedFields []string) {
+	t.Helper()
+
+	if len(expectedFields) == 0 {
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		return
+	}
+
+	var validationErr *ClusterValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *ClusterValidationError, got %v", err)
+	}
+
+	fields := make([]string, 0, len(validationErr.Violations))
+	for _, v := range validationErr.Violations {
+		fields = append(fields, v.Field)
+	}
+	if !stringSliceEqual(fields, expectedFields) {
+		t.Errorf("Expected violations on %v, got %v", expectedFields, validationErr.Violations)
+	}
+}
+
+func TestClusterValidator_ConcurrentFetch(t *testing.T) {
+	var regionCalls int32
+	requested := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&regionCalls, 1)
+		requested <- struct{}{}
+		<-release
+		json.NewEncoder(w).Encode(&models.OpenapiListProviderRegionsResp{Items: testProviderRegions().items})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test_public", "test_private")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.baseURL = server.URL
+
+	validator := NewClusterValidator(client, 0)
+	waiting := make(chan struct{}, 6)
+	validator.waitHook = func() { waiting <- struct{}{} }
+
+	// A caller whose ctx ends gives up without waiting for the slow fetch
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-requested
+		cancel()
+	}()
+	if err := validator.ValidateCreateClusterWithContext(ctx, validCreateClusterReq()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context canceled, got %v", err)
+	}
+	<-waiting
+
+	// Concurrent callers share the fetch still in flight
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- validator.ValidateCreateCluster(validCreateClusterReq())
+		}()
+	}
+	for i := 0; i < 5; i++ {
+		<-waiting
+	}
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	}
+	if calls := atomic.LoadInt32(&regionCalls); calls != 1 {
+		t.Errorf("Expected 1 provider regions fetch, got %d", calls)
+	}
+}
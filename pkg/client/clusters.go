@@ -2,27 +2,36 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
+	"time"
 
+	"github.com/5st7/tidb-cloud-go/pkg/errors"
 	"github.com/5st7/tidb-cloud-go/pkg/models"
 )
 
-// ListClusters lists all clusters in a project
+// ListClusters lists the first page of clusters in a project
 func (c *Client) ListClusters(projectID string) (*models.OpenapiListClustersOfProjectResp, error) {
+	return c.ListClustersWithOptions(context.Background(), projectID, nil)
+}
+
+// ListClustersWithOptions lists one page of clusters in a project
+func (c *Client) ListClustersWithOptions(ctx context.Context, projectID string, opts *ListOptions) (*models.OpenapiListClustersOfProjectResp, error) {
 	if projectID == "" {
-		return nil, fmt.Errorf("project ID is required")
+		return nil, errors.Required("project ID")
 	}
 
-	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters", c.baseURL, APIVersion, projectID)
+	url := withListOptions(fmt.Sprintf("%s/api/%s/projects/%s/clusters", c.baseURL, c.apiVersion, projectID), opts)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.doRequest(req)
+	resp, err := c.doRequestWithRetry(ctx, OpListClusters, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -40,23 +49,39 @@ func (c *Client) ListClusters(projectID string) (*models.OpenapiListClustersOfPr
 	return &clusters, nil
 }
 
+// AllClusters iterates over every cluster in a project, page by page
+func (c *Client) AllClusters(ctx context.Context, projectID string) iter.Seq2[*models.OpenapiClusterItem, error] {
+	return paginate(ctx, func(ctx context.Context, opts *ListOptions) ([]*models.OpenapiClusterItem, int64, error) {
+		resp, err := c.ListClustersWithOptions(ctx, projectID, opts)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp.Items, totalValue(resp.Total), nil
+	})
+}
+
 // GetCluster gets a cluster by ID
 func (c *Client) GetCluster(projectID, clusterID string) (*models.OpenapiClusterItem, error) {
+	return c.GetClusterWithContext(context.Background(), projectID, clusterID)
+}
+
+// GetClusterWithContext gets a cluster by ID using ctx for cancellation and deadlines
+func (c *Client) GetClusterWithContext(ctx context.Context, projectID, clusterID string) (*models.OpenapiClusterItem, error) {
 	if projectID == "" {
-		return nil, fmt.Errorf("project ID is required")
+		return nil, errors.Required("project ID")
 	}
 	if clusterID == "" {
-		return nil, fmt.Errorf("cluster ID is required")
+		return nil, errors.Required("cluster ID")
 	}
 
-	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s", c.baseURL, APIVersion, projectID, clusterID)
+	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s", c.baseURL, c.apiVersion, projectID, clusterID)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.doRequest(req)
+	resp, err := c.doRequestWithRetry(ctx, OpGetCluster, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -76,14 +101,19 @@ func (c *Client) GetCluster(projectID, clusterID string) (*models.OpenapiCluster
 
 // CreateCluster creates a new cluster
 func (c *Client) CreateCluster(projectID string, req *models.OpenapiCreateClusterReq) (*models.OpenapiCreateClusterResp, error) {
+	return c.CreateClusterWithContext(context.Background(), projectID, req)
+}
+
+// CreateClusterWithContext creates a new cluster using ctx for cancellation and deadlines
+func (c *Client) CreateClusterWithContext(ctx context.Context, projectID string, req *models.OpenapiCreateClusterReq) (*models.OpenapiCreateClusterResp, error) {
 	if projectID == "" {
-		return nil, fmt.Errorf("project ID is required")
+		return nil, errors.Required("project ID")
 	}
 	if req == nil {
-		return nil, fmt.Errorf("request is required")
+		return nil, errors.Required("request")
 	}
 
-	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters", c.baseURL, APIVersion, projectID)
+	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters", c.baseURL, c.apiVersion, projectID)
 
 	reqBody, err := json.Marshal(req)
 	if err != nil {
@@ -96,10 +126,25 @@ func (c *Client) CreateCluster(projectID string, req *models.OpenapiCreateCluste
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.doRequest(httpReq)
+	// Before resending, look for the cluster in case an earlier attempt created it
+	since := time.Now()
+	var created *models.OpenapiCreateClusterResp
+	var reconcile reconcileFunc
+	if req.Name != nil {
+		reconcile = func(ctx context.Context) (bool, error) {
+			var err error
+			created, err = c.findCreatedCluster(ctx, projectID, req, since)
+			return created != nil, err
+		}
+	}
+
+	resp, reconciled, err := c.doRequestWithReconcile(ctx, OpCreateCluster, httpReq, reconcile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
+	if reconciled {
+		return created, nil
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
@@ -116,17 +161,22 @@ func (c *Client) CreateCluster(projectID string, req *models.OpenapiCreateCluste
 
 // UpdateCluster updates an existing cluster
 func (c *Client) UpdateCluster(projectID, clusterID string, req *models.OpenapiUpdateClusterReq) error {
+	return c.UpdateClusterWithContext(context.Background(), projectID, clusterID, req)
+}
+
+// UpdateClusterWithContext updates an existing cluster using ctx for cancellation and deadlines
+func (c *Client) UpdateClusterWithContext(ctx context.Context, projectID, clusterID string, req *models.OpenapiUpdateClusterReq) error {
 	if projectID == "" {
-		return fmt.Errorf("project ID is required")
+		return errors.Required("project ID")
 	}
 	if clusterID == "" {
-		return fmt.Errorf("cluster ID is required")
+		return errors.Required("cluster ID")
 	}
 	if req == nil {
-		return fmt.Errorf("request is required")
+		return errors.Required("request")
 	}
 
-	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s", c.baseURL, APIVersion, projectID, clusterID)
+	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s", c.baseURL, c.apiVersion, projectID, clusterID)
 
 	reqBody, err := json.Marshal(req)
 	if err != nil {
@@ -139,7 +189,7 @@ func (c *Client) UpdateCluster(projectID, clusterID string, req *models.OpenapiU
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.doRequest(httpReq)
+	resp, err := c.doRequestWithRetry(ctx, OpUpdateCluster, httpReq)
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -154,24 +204,32 @@ func (c *Client) UpdateCluster(projectID, clusterID string, req *models.OpenapiU
 
 // DeleteCluster deletes a cluster
 func (c *Client) DeleteCluster(projectID, clusterID string) error {
+	return c.DeleteClusterWithContext(context.Background(), projectID, clusterID)
+}
+
+// DeleteClusterWithContext deletes a cluster using ctx for cancellation and deadlines
+func (c *Client) DeleteClusterWithContext(ctx context.Context, projectID, clusterID string) error {
 	if projectID == "" {
-		return fmt.Errorf("project ID is required")
+		return errors.Required("project ID")
 	}
 	if clusterID == "" {
-		return fmt.Errorf("cluster ID is required")
+		return errors.Required("cluster ID")
 	}
 
-	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s", c.baseURL, APIVersion, projectID, clusterID)
+	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s", c.baseURL, c.apiVersion, projectID, clusterID)
 
 	req, err := http.NewRequest("DELETE", url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.doRequest(req)
+	resp, reconciled, err := c.doRequestWithReconcile(ctx, OpDeleteCluster, req, nil)
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
 	}
+	if reconciled {
+		return nil
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
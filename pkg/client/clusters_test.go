@@ -1,10 +1,13 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/5st7/tidb-cloud-go/pkg/models"
 )
@@ -44,7 +47,7 @@ func TestClient_ListClusters(t *testing.T) {
 							CloudProvider: stringPtr("AWS"),
 							Region:        stringPtr("us-west-2"),
 							Status: &models.OpenapiClusterItemStatus{
-								ClusterStatus: stringPtr("AVAILABLE"),
+								ClusterStatus: clusterStatusPtr(models.OpenapiClusterStatusAVAILABLE),
 							},
 						},
 					},
@@ -135,7 +138,7 @@ func TestClient_GetCluster(t *testing.T) {
 					CloudProvider: stringPtr("AWS"),
 					Region:        stringPtr("us-west-2"),
 					Status: &models.OpenapiClusterItemStatus{
-						ClusterStatus: stringPtr("AVAILABLE"),
+						ClusterStatus: clusterStatusPtr(models.OpenapiClusterStatusAVAILABLE),
 					},
 				}
 
@@ -188,6 +191,77 @@ func TestClient_GetCluster(t *testing.T) {
 	}
 }
 
+func TestClient_GetCluster_StatusDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "cluster456",
+			"project_id": "project123",
+			"status": {
+				"tidb_version": "v7.5.0",
+				"cluster_status": "MODIFYING",
+				"node_map": {
+					"tidb": [
+						{"node_name": "tidb-0", "availability_zone": "us-west-2a", "node_size": "8C16G", "vcpu_num": 8, "ram_bytes": "17179869184", "status": "NODE_STATUS_AVAILABLE"}
+					],
+					"tikv": [
+						{"node_name": "tikv-0", "availability_zone": "us-west-2a", "node_size": "8C32G", "vcpu_num": 8, "ram_bytes": "34359738368", "storage_size_gib": 500, "status": "NODE_STATUS_AVAILABLE"},
+						{"node_name": "tikv-1", "availability_zone": "us-west-2b", "node_size": "8C32G", "vcpu_num": 8, "ram_bytes": "34359738368", "storage_size_gib": 500, "status": "NODE_STATUS_CREATING"}
+					],
+					"tiflash": []
+				},
+				"connection_strings": {
+					"default_user": "root",
+					"standard": {"host": "tidb.example.com", "port": 4000},
+					"vpc_peering": {"host": "private-tidb.example.com", "port": 4000}
+				}
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test_public", "test_private")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.baseURL = server.URL
+
+	cluster, err := client.GetCluster("project123", "cluster456")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cluster.ProjectID == nil || *cluster.ProjectID != "project123" {
+		t.Errorf("Expected project ID project123, got %v", cluster.ProjectID)
+	}
+
+	status := cluster.Status
+	if *status.TiDBVersion != "v7.5.0" {
+		t.Errorf("Expected TiDB version v7.5.0, got %s", *status.TiDBVersion)
+	}
+	if *status.ClusterStatus != models.OpenapiClusterStatusMODIFYING {
+		t.Errorf("Expected status MODIFYING, got %s", *status.ClusterStatus)
+	}
+
+	if len(status.NodeMap.TiDB) != 1 || len(status.NodeMap.TiKV) != 2 {
+		t.Fatalf("Unexpected node map %+v", status.NodeMap)
+	}
+	tikv := status.NodeMap.TiKV[1]
+	if *tikv.AvailabilityZone != "us-west-2b" {
+		t.Errorf("Expected AZ us-west-2b, got %s", *tikv.AvailabilityZone)
+	}
+	if *tikv.Status != models.OpenapiNodeStatusNODESTATUSCREATING {
+		t.Errorf("Expected node status creating, got %s", *tikv.Status)
+	}
+	if *tikv.StorageSizeGib != 500 || *tikv.VCPUNum != 8 {
+		t.Errorf("Unexpected TiKV node size %+v", tikv)
+	}
+
+	if *status.ConnectionStrings.VPCPeering.Host != "private-tidb.example.com" {
+		t.Errorf("Expected VPC peering host, got %s", *status.ConnectionStrings.VPCPeering.Host)
+	}
+}
+
 func TestClient_CreateCluster(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -294,3 +368,41 @@ func TestClient_CreateCluster(t *testing.T) {
 		})
 	}
 }
+
+func clusterStatusPtr(s models.OpenapiClusterStatus) *models.OpenapiClusterStatus {
+	return &s
+}
+
+func TestClient_GetClusterWithContext_Cancellation(t *testing.T) {
+	callCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		w.WriteHeader(http.StatusTooManyRequests)
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 49900007, "message": "rate limited"})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test_public", "test_private")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.baseURL = server.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = client.GetClusterWithContext(ctx, "project123", "cluster456")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if ctx.Err() == nil || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected retry backoff to stop at the deadline, took %v", elapsed)
+	}
+	if callCount != 1 {
+		t.Errorf("Expected 1 request, got %d", callCount)
+	}
+}
@@ -0,0 +1,191 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/5st7/tidb-cloud-go/pkg/errors"
+	"github.com/5st7/tidb-cloud-go/pkg/models"
+)
+
+// MissingAWSCMEKError is returned by ValidateAWSCMEKRegions when one or more
+// regions of a CMEK-enabled project have no customer-managed encryption key.
+type MissingAWSCMEKError struct {
+	ProjectID string
+	Regions   []string
+}
+
+// Error implements the error interface.
+func (e *MissingAWSCMEKError) Error() string {
+	return fmt.Sprintf("AWS CMEK is not configured in project %s for regions: %s", e.ProjectID, strings.Join(e.Regions, ", "))
+}
+
+// ListAWSCMEK lists the AWS customer-managed encryption keys of a project.
+// Each item maps an AWS region to the KMS key used to encrypt data at rest
+// for TiDB Cloud Dedicated clusters in that region.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - projectID: The ID of the project
+//
+// Returns:
+//   - *models.OpenapiListAwsCmekResp: The configured keys by region
+//   - error: An error if the request fails or parameters are invalid
+func (c *Client) ListAWSCMEK(ctx context.Context, projectID string) (*models.OpenapiListAwsCmekResp, error) {
+	if projectID == "" {
+		return nil, errors.Required("project ID")
+	}
+
+	url := fmt.Sprintf("%s/api/%s/projects/%s/aws-cmek", c.baseURL, c.apiVersion, projectID)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.doRequestWithRetry(ctx, OpListAWSCMEK, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	var cmekResp models.OpenapiListAwsCmekResp
+	if err := json.NewDecoder(resp.Body).Decode(&cmekResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &cmekResp, nil
+}
+
+// CreateAWSCMEK configures AWS customer-managed encryption keys for a project.
+// The project must have been created with aws_cmek_enabled set to true.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - projectID: The ID of the project
+//   - req: The keys to configure, one per region
+//
+// Returns:
+//   - error: An error if the request fails or parameters are invalid
+func (c *Client) CreateAWSCMEK(ctx context.Context, projectID string, req *models.OpenapiCreateAwsCmekReq) error {
+	if projectID == "" {
+		return errors.Required("project ID")
+	}
+	if req == nil {
+		return errors.Required("request")
+	}
+	if len(req.Specs) == 0 {
+		return errors.Invalid("CMEK specs", "at least one CMEK spec is required")
+	}
+	for _, spec := range req.Specs {
+		if spec == nil || spec.Region == nil || *spec.Region == "" {
+			return errors.Required("CMEK region")
+		}
+		if spec.KmsArn == nil || *spec.KmsArn == "" {
+			return errors.Invalid("CMEK KMS ARN", "CMEK KMS ARN is required for region %s", *spec.Region)
+		}
+	}
+
+	url := fmt.Sprintf("%s/api/%s/projects/%s/aws-cmek", c.baseURL, c.apiVersion, projectID)
+
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doRequestWithRetry(ctx, OpCreateAWSCMEK, httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// ValidateAWSCMEKRegions checks that every given region has an AWS CMEK entry
+// in the project. Call it before CreateCluster to avoid deploying a cluster
+// whose data would not be encrypted with a customer-managed key.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - projectID: The ID of the project
+//   - regions: The AWS regions clusters are deployed into
+//
+// Returns:
+//   - error: A *MissingAWSCMEKError listing regions without a key, or an error if the request fails
+func (c *Client) ValidateAWSCMEKRegions(ctx context.Context, projectID string, regions ...string) error {
+	cmek, err := c.ListAWSCMEK(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	configured := make(map[string]bool, len(cmek.Items))
+	for _, item := range cmek.Items {
+		if item != nil && item.Region != nil && item.KmsArn != nil && *item.KmsArn != "" {
+			configured[*item.Region] = true
+		}
+	}
+
+	seen := make(map[string]bool, len(regions))
+	var missing []string
+	for _, region := range regions {
+		if region == "" || seen[region] {
+			continue
+		}
+		seen[region] = true
+		if !configured[region] {
+			missing = append(missing, region)
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return &MissingAWSCMEKError{ProjectID: projectID, Regions: missing}
+	}
+
+	return nil
+}
+
+// ValidateClusterAWSCMEK checks that the region of a cluster creation request
+// has an AWS CMEK entry in the project. Requests for other cloud providers are
+// accepted without a lookup.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - projectID: The ID of the project the cluster will be created in
+//   - req: The cluster creation request to check
+//
+// Returns:
+//   - error: A *MissingAWSCMEKError if the region has no key, or an error if the request fails
+func (c *Client) ValidateClusterAWSCMEK(ctx context.Context, projectID string, req *models.OpenapiCreateClusterReq) error {
+	if req == nil {
+		return errors.Required("request")
+	}
+	if req.CloudProvider == nil || !strings.EqualFold(*req.CloudProvider, "AWS") {
+		return nil
+	}
+	if req.Region == nil || *req.Region == "" {
+		return errors.Required("region")
+	}
+
+	return c.ValidateAWSCMEKRegions(ctx, projectID, *req.Region)
+}
@@ -0,0 +1,197 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/5st7/tidb-cloud-go/pkg/models"
+)
+
+func TestClient_ListAWSCMEK(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("Expected GET, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/v1beta/projects/test-project/aws-cmek" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(&models.OpenapiListAwsCmekResp{
+			Items: []*models.OpenapiAwsCmekSpec{
+				{Region: stringPtr("us-west-2"), KmsArn: stringPtr("arn:aws:kms:us-west-2:123456789012:key/abc")},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", "test-secret")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.baseURL = server.URL
+
+	resp, err := client.ListAWSCMEK(context.Background(), "test-project")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(resp.Items) != 1 || *resp.Items[0].Region != "us-west-2" {
+		t.Errorf("Unexpected CMEK items %+v", resp.Items)
+	}
+
+	if _, err := client.ListAWSCMEK(context.Background(), ""); err == nil {
+		t.Error("Expected error for empty project ID, got nil")
+	}
+}
+
+func TestClient_CreateAWSCMEK(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           *models.OpenapiCreateAwsCmekReq
+		expectedError string
+	}{
+		{
+			name: "successful create",
+			req: &models.OpenapiCreateAwsCmekReq{
+				Specs: []*models.OpenapiAwsCmekSpec{
+					{Region: stringPtr("us-west-2"), KmsArn: stringPtr("arn:aws:kms:us-west-2:123456789012:key/abc")},
+				},
+			},
+		},
+		{
+			name:          "nil request",
+			req:           nil,
+			expectedError: "request is required",
+		},
+		{
+			name:          "no specs",
+			req:           &models.OpenapiCreateAwsCmekReq{},
+			expectedError: "at least one CMEK spec is required",
+		},
+		{
+			name: "missing KMS ARN",
+			req: &models.OpenapiCreateAwsCmekReq{
+				Specs: []*models.OpenapiAwsCmekSpec{{Region: stringPtr("us-east-1")}},
+			},
+			expectedError: "CMEK KMS ARN is required for region us-east-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.expectedError != "" {
+					t.Error("Server should not be called for invalid request")
+				}
+				if r.Method != "POST" {
+					t.Errorf("Expected POST, got %s", r.Method)
+				}
+
+				var req models.OpenapiCreateAwsCmekReq
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("Failed to decode request body: %v", err)
+				}
+				if len(req.Specs) != 1 {
+					t.Errorf("Expected 1 spec, got %d", len(req.Specs))
+				}
+
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			client, err := NewClient("test-key", "test-secret")
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			client.baseURL = server.URL
+
+			err = client.CreateAWSCMEK(context.Background(), "test-project", tt.req)
+
+			if tt.expectedError != "" {
+				if err == nil {
+					t.Errorf("Expected error %q, got nil", tt.expectedError)
+				} else if err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got %q", tt.expectedError, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_ValidateAWSCMEKRegions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(&models.OpenapiListAwsCmekResp{
+			Items: []*models.OpenapiAwsCmekSpec{
+				{Region: stringPtr("us-west-2"), KmsArn: stringPtr("arn:aws:kms:us-west-2:123456789012:key/abc")},
+				{Region: stringPtr("eu-central-1"), KmsArn: stringPtr("arn:aws:kms:eu-central-1:123456789012:key/def")},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", "test-secret")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.baseURL = server.URL
+
+	tests := []struct {
+		name            string
+		regions         []string
+		expectedMissing []string
+	}{
+		{
+			name:    "all regions configured",
+			regions: []string{"us-west-2", "eu-central-1"},
+		},
+		{
+			name:            "missing regions",
+			regions:         []string{"us-west-2", "us-east-1", "ap-northeast-1", "us-east-1"},
+			expectedMissing: []string{"ap-northeast-1", "us-east-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.ValidateAWSCMEKRegions(context.Background(), "test-project", tt.regions...)
+
+			if tt.expectedMissing == nil {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			var missingErr *MissingAWSCMEKError
+			if !errors.As(err, &missingErr) {
+				t.Fatalf("Expected *MissingAWSCMEKError, got %v", err)
+			}
+			if !stringSliceEqual(missingErr.Regions, tt.expectedMissing) {
+				t.Errorf("Expected missing regions %v, got %v", tt.expectedMissing, missingErr.Regions)
+			}
+		})
+	}
+
+	t.Run("cluster request", func(t *testing.T) {
+		awsReq := &models.OpenapiCreateClusterReq{CloudProvider: stringPtr("AWS"), Region: stringPtr("us-east-1")}
+		if err := client.ValidateClusterAWSCMEK(context.Background(), "test-project", awsReq); err == nil {
+			t.Error("Expected error for AWS region without CMEK, got nil")
+		}
+
+		gcpReq := &models.OpenapiCreateClusterReq{CloudProvider: stringPtr("GCP"), Region: stringPtr("us-central1")}
+		if err := client.ValidateClusterAWSCMEK(context.Background(), "test-project", gcpReq); err != nil {
+			t.Errorf("Expected no error for GCP cluster, got %v", err)
+		}
+	})
+}
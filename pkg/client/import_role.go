@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/5st7/tidb-cloud-go/pkg/errors"
+	"github.com/5st7/tidb-cloud-go/pkg/models"
+)
+
+// GetImportRoleInfo retrieves the identity that import tasks of a cluster run as.
+// For clusters on AWS the response contains the account ID and external ID to
+// trust in the role referenced by aws_assume_role_access; for clusters on GCP it
+// contains the service account that needs access to the GCS bucket.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - projectID: The ID of the project containing the cluster
+//   - clusterID: The ID of the cluster
+//
+// Returns:
+//   - *models.OpenapiImportTaskRoleInfo: The AWS or GCP import role information
+//   - error: An error if the request fails or parameters are invalid
+func (c *Client) GetImportRoleInfo(ctx context.Context, projectID, clusterID string) (*models.OpenapiImportTaskRoleInfo, error) {
+	if projectID == "" {
+		return nil, errors.Required("project ID")
+	}
+	if clusterID == "" {
+		return nil, errors.Required("cluster ID")
+	}
+
+	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/imports/role_info", c.baseURL, c.apiVersion, projectID, clusterID)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.doRequestWithRetry(ctx, OpGetImportRoleInfo, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	var roleInfo models.OpenapiImportTaskRoleInfo
+	if err := json.NewDecoder(resp.Body).Decode(&roleInfo); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &roleInfo, nil
+}
+
+type awsPolicyDocument struct {
+	Version   string               `json:"Version"`
+	Statement []awsPolicyStatement `json:"Statement"`
+}
+
+type awsPolicyStatement struct {
+	Effect    string                       `json:"Effect"`
+	Principal map[string]string            `json:"Principal"`
+	Action    string                       `json:"Action"`
+	Condition map[string]map[string]string `json:"Condition,omitempty"`
+}
+
+type gcpIAMPolicy struct {
+	Bindings []gcpIAMBinding `json:"bindings"`
+}
+
+type gcpIAMBinding struct {
+	Role    string   `json:"role"`
+	Members []string `json:"members"`
+}
+
+// AWSImportTrustPolicy renders the IAM trust policy to attach to the role that
+// is passed as aws_assume_role_access.assume_role of an import source.
+// The policy lets the TiDB Cloud import account assume the role, and only with
+// the external ID bound to the cluster.
+//
+// Parameters:
+//   - info: The role information returned by GetImportRoleInfo
+//
+// Returns:
+//   - []byte: The trust policy as indented JSON
+//   - error: An error if the response holds no AWS import role
+func AWSImportTrustPolicy(info *models.OpenapiImportTaskRoleInfo) ([]byte, error) {
+	if info == nil || info.AwsImportRole == nil {
+		return nil, errors.Required("AWS import role info")
+	}
+	role := info.AwsImportRole
+	if role.AccountID == nil || *role.AccountID == "" {
+		return nil, errors.Required("AWS import role account ID")
+	}
+	if role.ExternalID == nil || *role.ExternalID == "" {
+		return nil, errors.Required("AWS import role external ID")
+	}
+
+	policy := awsPolicyDocument{
+		Version: "2012-10-17",
+		Statement: []awsPolicyStatement{
+			{
+				Effect:    "Allow",
+				Principal: map[string]string{"AWS": fmt.Sprintf("arn:aws:iam::%s:root", *role.AccountID)},
+				Action:    "sts:AssumeRole",
+				Condition: map[string]map[string]string{
+					"StringEquals": {"sts:ExternalId": *role.ExternalID},
+				},
+			},
+		},
+	}
+
+	return json.MarshalIndent(policy, "", "  ")
+}
+
+// GCPImportBucketBinding renders the IAM bindings to add to the policy of the
+// GCS bucket that an import reads from, granting them to the import service account.
+// When role is empty, roles/storage.legacyBucketReader and roles/storage.objectViewer
+// are bound, which together cover storage.buckets.get, storage.objects.list and
+// storage.objects.get as required by import tasks.
+//
+// Parameters:
+//   - info: The role information returned by GetImportRoleInfo
+//   - role: An optional custom role, e.g. "projects/my-project/roles/tidbImport"
+//
+// Returns:
+//   - []byte: The bucket IAM policy bindings as indented JSON
+//   - error: An error if the response holds no GCP import role
+func GCPImportBucketBinding(info *models.OpenapiImportTaskRoleInfo, role string) ([]byte, error) {
+	if info == nil || info.GcpImportRole == nil {
+		return nil, errors.Required("GCP import role info")
+	}
+	if info.GcpImportRole.AccountID == nil || *info.GcpImportRole.AccountID == "" {
+		return nil, errors.Required("GCP import role account ID")
+	}
+
+	roles := []string{"roles/storage.legacyBucketReader", "roles/storage.objectViewer"}
+	if role != "" {
+		roles = []string{role}
+	}
+
+	member := "serviceAccount:" + *info.GcpImportRole.AccountID
+	policy := gcpIAMPolicy{}
+	for _, r := range roles {
+		policy.Bindings = append(policy.Bindings, gcpIAMBinding{Role: r, Members: []string{member}})
+	}
+
+	return json.MarshalIndent(policy, "", "  ")
+}
@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/5st7/tidb-cloud-go/pkg/models"
+)
+
+func TestClient_GetImportRoleInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("Expected GET, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/v1beta/projects/test-project/clusters/test-cluster/imports/role_info" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"aws_import_role": {"account_id": "999999999999", "external_id": "ext-123"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", "test-secret")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.baseURL = server.URL
+
+	info, err := client.GetImportRoleInfo(context.Background(), "test-project", "test-cluster")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if info.AwsImportRole == nil || *info.AwsImportRole.ExternalID != "ext-123" {
+		t.Errorf("Expected AWS import role with external ID ext-123, got %+v", info.AwsImportRole)
+	}
+	if info.GcpImportRole != nil {
+		t.Errorf("Expected no GCP import role, got %+v", info.GcpImportRole)
+	}
+
+	if _, err := client.GetImportRoleInfo(context.Background(), "", "test-cluster"); err == nil {
+		t.Error("Expected error for empty project ID, got nil")
+	}
+}
+
+func TestAWSImportTrustPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		info        *models.OpenapiImportTaskRoleInfo
+		expectedErr bool
+	}{
+		{
+			name: "aws role info",
+			info: &models.OpenapiImportTaskRoleInfo{
+				AwsImportRole: &models.OpenapiAwsImportTaskRoleInfo{
+					AccountID:  stringPtr("999999999999"),
+					ExternalID: stringPtr("ext-123"),
+				},
+			},
+		},
+		{
+			name: "gcp role info only",
+			info: &models.OpenapiImportTaskRoleInfo{
+				GcpImportRole: &models.OpenapiGcpImportTaskRoleInfo{AccountID: stringPtr("sa@example.com")},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "missing external ID",
+			info: &models.OpenapiImportTaskRoleInfo{
+				AwsImportRole: &models.OpenapiAwsImportTaskRoleInfo{AccountID: stringPtr("999999999999")},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := AWSImportTrustPolicy(tt.info)
+			if tt.expectedErr {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var doc awsPolicyDocument
+			if err := json.Unmarshal(policy, &doc); err != nil {
+				t.Fatalf("Policy is not valid JSON: %v", err)
+			}
+			if len(doc.Statement) != 1 {
+				t.Fatalf("Expected 1 statement, got %d", len(doc.Statement))
+			}
+			stmt := doc.Statement[0]
+			if stmt.Principal["AWS"] != "arn:aws:iam::999999999999:root" {
+				t.Errorf("Unexpected principal %v", stmt.Principal)
+			}
+			if stmt.Action != "sts:AssumeRole" {
+				t.Errorf("Expected sts:AssumeRole, got %s", stmt.Action)
+			}
+			if stmt.Condition["StringEquals"]["sts:ExternalId"] != "ext-123" {
+				t.Errorf("Unexpected condition %v", stmt.Condition)
+			}
+		})
+	}
+}
+
+func TestGCPImportBucketBinding(t *testing.T) {
+	info := &models.OpenapiImportTaskRoleInfo{
+		GcpImportRole: &models.OpenapiGcpImportTaskRoleInfo{AccountID: stringPtr("sa@example.com")},
+	}
+
+	tests := []struct {
+		name          string
+		role          string
+		expectedRoles []string
+	}{
+		{
+			name:          "default roles",
+			expectedRoles: []string{"roles/storage.legacyBucketReader", "roles/storage.objectViewer"},
+		},
+		{
+			name:          "custom role",
+			role:          "projects/p/roles/tidbImport",
+			expectedRoles: []string{"projects/p/roles/tidbImport"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			binding, err := GCPImportBucketBinding(info, tt.role)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var policy gcpIAMPolicy
+			if err := json.Unmarshal(binding, &policy); err != nil {
+				t.Fatalf("Binding is not valid JSON: %v", err)
+			}
+			if len(policy.Bindings) != len(tt.expectedRoles) {
+				t.Fatalf("Expected %d bindings, got %d", len(tt.expectedRoles), len(policy.Bindings))
+			}
+			for i, b := range policy.Bindings {
+				if b.Role != tt.expectedRoles[i] {
+					t.Errorf("Expected role %s, got %s", tt.expectedRoles[i], b.Role)
+				}
+				if len(b.Members) != 1 || b.Members[0] != "serviceAccount:sa@example.com" {
+					t.Errorf("Unexpected members %v", b.Members)
+				}
+			}
+		})
+	}
+
+	if _, err := GCPImportBucketBinding(&models.OpenapiImportTaskRoleInfo{}, ""); err == nil {
+		t.Error("Expected error without GCP import role, got nil")
+	}
+}
@@ -0,0 +1,472 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/5st7/tidb-cloud-go/pkg/errors"
+	"github.com/5st7/tidb-cloud-go/pkg/models"
+)
+
+// MaxLocalFileSize is the largest local file, in bytes, that the API accepts
+// for UploadImportFile.
+const MaxLocalFileSize = 50 * 1024 * 1024
+
+// ListImports lists the first page of import tasks of a cluster.
+// Each item carries the task metadata, the source and target specification,
+// and the current phase and progress of the import.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - projectID: The ID of the project containing the cluster
+//   - clusterID: The ID of the cluster
+//
+// Returns:
+//   - *models.OpenapiListImportTasksResp: A list of import tasks
+//   - error: An error if the request fails or parameters are invalid
+func (c *Client) ListImports(ctx context.Context, projectID, clusterID string) (*models.OpenapiListImportTasksResp, error) {
+	return c.ListImportsWithOptions(ctx, projectID, clusterID, nil)
+}
+
+// ListImportsWithOptions lists one page of import tasks of a cluster.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - projectID: The ID of the project containing the cluster
+//   - clusterID: The ID of the cluster
+//   - opts: The page to retrieve; nil uses the server defaults
+//
+// Returns:
+//   - *models.OpenapiListImportTasksResp: A page of import tasks and the total count
+//   - error: An error if the request fails or parameters are invalid
+func (c *Client) ListImportsWithOptions(ctx context.Context, projectID, clusterID string, opts *ListOptions) (*models.OpenapiListImportTasksResp, error) {
+	if projectID == "" {
+		return nil, errors.Required("project ID")
+	}
+	if clusterID == "" {
+		return nil, errors.Required("cluster ID")
+	}
+
+	url := withListOptions(fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/imports", c.baseURL, c.apiVersion, projectID, clusterID), opts)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.doRequestWithRetry(ctx, OpListImports, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	var importsResp models.OpenapiListImportTasksResp
+	if err := json.NewDecoder(resp.Body).Decode(&importsResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &importsResp, nil
+}
+
+// AllImports returns an iterator over every import task of a cluster,
+// fetching further pages as the iteration proceeds.
+// An error stops the iteration and is yielded with a nil item.
+func (c *Client) AllImports(ctx context.Context, projectID, clusterID string) iter.Seq2[*models.OpenapiImportItem, error] {
+	return paginate(ctx, func(ctx context.Context, opts *ListOptions) ([]*models.OpenapiImportItem, int64, error) {
+		resp, err := c.ListImportsWithOptions(ctx, projectID, clusterID, opts)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp.Items, totalValue(resp.Total), nil
+	})
+}
+
+// GetImport retrieves a single import task of a cluster.
+// Use the returned status to follow the task through its phases,
+// from PREPARING and IMPORTING to COMPLETED, FAILED or CANCELED.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - projectID: The ID of the project containing the cluster
+//   - clusterID: The ID of the cluster
+//   - importID: The ID of the import task
+//
+// Returns:
+//   - *models.OpenapiImportItem: The import task details
+//   - error: An error if the request fails or parameters are invalid
+func (c *Client) GetImport(ctx context.Context, projectID, clusterID, importID string) (*models.OpenapiImportItem, error) {
+	if projectID == "" {
+		return nil, errors.Required("project ID")
+	}
+	if clusterID == "" {
+		return nil, errors.Required("cluster ID")
+	}
+	if importID == "" {
+		return nil, errors.Required("import ID")
+	}
+
+	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/imports/%s", c.baseURL, c.apiVersion, projectID, clusterID, importID)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.doRequestWithRetry(ctx, OpGetImport, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	var importItem models.OpenapiImportItem
+	if err := json.NewDecoder(resp.Body).Decode(&importItem); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &importItem, nil
+}
+
+// CreateImport creates an import task that loads data into a cluster.
+// The source can be Amazon S3, Google Cloud Storage or a previously uploaded
+// local file, in CSV, Parquet, SQL or Aurora snapshot format.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - projectID: The ID of the project containing the cluster
+//   - clusterID: The ID of the cluster to import data into
+//   - req: The import task creation request
+//
+// Returns:
+//   - *models.OpenapiCreateImportTaskResp: The ID of the created import task
+//   - error: An error if the request fails or parameters are invalid
+func (c *Client) CreateImport(ctx context.Context, projectID, clusterID string, req *models.OpenapiCreateImportTaskReq) (*models.OpenapiCreateImportTaskResp, error) {
+	if projectID == "" {
+		return nil, errors.Required("project ID")
+	}
+	if clusterID == "" {
+		return nil, errors.Required("cluster ID")
+	}
+	if req == nil {
+		return nil, errors.Required("request")
+	}
+
+	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/imports", c.baseURL, c.apiVersion, projectID, clusterID)
+
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doRequestWithRetry(ctx, OpCreateImport, httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	var createResp models.OpenapiCreateImportTaskResp
+	if err := json.NewDecoder(resp.Body).Decode(&createResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &createResp, nil
+}
+
+// CancelImport cancels an import task of a cluster.
+// Only tasks in the PREPARING or IMPORTING phase can be canceled; the task
+// moves to CANCELING and then CANCELED once the server has stopped it.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - projectID: The ID of the project containing the cluster
+//   - clusterID: The ID of the cluster
+//   - importID: The ID of the import task to cancel
+//
+// Returns:
+//   - error: An error if the request fails or parameters are invalid
+func (c *Client) CancelImport(ctx context.Context, projectID, clusterID, importID string) error {
+	if projectID == "" {
+		return errors.Required("project ID")
+	}
+	if clusterID == "" {
+		return errors.Required("cluster ID")
+	}
+	if importID == "" {
+		return errors.Required("import ID")
+	}
+
+	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/imports/%s", c.baseURL, c.apiVersion, projectID, clusterID, importID)
+
+	action := models.UpdateImportTaskReqImportTaskActionCANCEL
+	reqBody, err := json.Marshal(&models.OpenapiUpdateImportTaskReq{Action: &action})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("PATCH", url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doRequestWithRetry(ctx, OpCancelImport, httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// UploadImportFile uploads a local CSV file so that it can be used as the
+// LOCAL_FILE source of an import task or a preview.
+// The file is gzip-compressed and base64-encoded while it is sent. Readers that
+// also implement io.Seeker (such as *os.File) are streamed from their current
+// offset and rewound when the request has to be repeated; other readers are
+// read into memory first.
+// Note: Uploading a local file is only available for TiDB Cloud Serverless clusters.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - projectID: The ID of the project containing the cluster
+//   - clusterID: The ID of the cluster
+//   - fileName: The name of the file; only its base name is sent
+//   - r: The file content, at most MaxLocalFileSize bytes
+//
+// Returns:
+//   - *models.OpenapiUploadLocalFileResp: The upload stub ID to reference as file://<id>/
+//   - error: An error if the request fails or parameters are invalid
+func (c *Client) UploadImportFile(ctx context.Context, projectID, clusterID, fileName string, r io.Reader) (*models.OpenapiUploadLocalFileResp, error) {
+	if projectID == "" {
+		return nil, errors.Required("project ID")
+	}
+	if clusterID == "" {
+		return nil, errors.Required("cluster ID")
+	}
+	if fileName == "" {
+		return nil, errors.Required("file name")
+	}
+	if r == nil {
+		return nil, errors.Required("file content")
+	}
+
+	var size int64
+	var getContent func() (io.Reader, error)
+	if seeker, ok := r.(io.ReadSeeker); ok {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		end, err := seeker.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		size = end - start
+		getContent = func() (io.Reader, error) {
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return nil, err
+			}
+			return seeker, nil
+		}
+	} else {
+		content, err := io.ReadAll(io.LimitReader(r, MaxLocalFileSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		size = int64(len(content))
+		getContent = func() (io.Reader, error) {
+			return bytes.NewReader(content), nil
+		}
+	}
+
+	if size > MaxLocalFileSize {
+		return nil, errors.Invalid("file size", "file size exceeds the maximum of %d bytes", MaxLocalFileSize)
+	}
+
+	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/imports/upload_file", c.baseURL, c.apiVersion, projectID, clusterID)
+
+	httpReq, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	// The transport closes the body of an attempt asynchronously, so the body
+	// of the previous attempt may still be reading the content. It is stopped
+	// before the content is rewound for the next one.
+	var mu sync.Mutex
+	var last *localFileBody
+	httpReq.GetBody = func() (io.ReadCloser, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if last != nil {
+			last.Close()
+		}
+		content, err := getContent()
+		if err != nil {
+			return nil, err
+		}
+		last = newLocalFileBody(filepath.Base(fileName), size, content)
+		return last, nil
+	}
+
+	resp, err := c.doRequestWithRetry(ctx, OpUploadImportFile, httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	var uploadResp models.OpenapiUploadLocalFileResp
+	if err := json.NewDecoder(resp.Body).Decode(&uploadResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &uploadResp, nil
+}
+
+// PreviewImport previews how TiDB Cloud will interpret the data of an import
+// before the import task is created. The result is organized by table and
+// contains the inferred schema and a sample of the rows.
+// Note: Currently only the LOCAL_FILE source type can be previewed.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - projectID: The ID of the project containing the cluster
+//   - clusterID: The ID of the cluster
+//   - req: The preview request containing the import specification
+//
+// Returns:
+//   - *models.OpenapiPreviewImportDataResp: The table schemas and sample rows
+//   - error: An error if the request fails or parameters are invalid
+func (c *Client) PreviewImport(ctx context.Context, projectID, clusterID string, req *models.OpenapiPreviewImportDataReq) (*models.OpenapiPreviewImportDataResp, error) {
+	if projectID == "" {
+		return nil, errors.Required("project ID")
+	}
+	if clusterID == "" {
+		return nil, errors.Required("cluster ID")
+	}
+	if req == nil {
+		return nil, errors.Required("request")
+	}
+
+	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/imports/preview", c.baseURL, c.apiVersion, projectID, clusterID)
+
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doRequestWithRetry(ctx, OpPreviewImport, httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	var previewResp models.OpenapiPreviewImportDataResp
+	if err := json.NewDecoder(resp.Body).Decode(&previewResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &previewResp, nil
+}
+
+// localFileBody is a request body encoded from the content of a local file by
+// a goroutine.
+type localFileBody struct {
+	*io.PipeReader
+	done chan struct{}
+}
+
+// Close stops the encoding and waits until the content is no longer read, so
+// that it can be rewound for another attempt.
+func (b *localFileBody) Close() error {
+	err := b.PipeReader.Close()
+	<-b.done
+	return err
+}
+
+// newLocalFileBody returns a request body that encodes an upload request
+// (models.OpenapiUploadLocalFileReq) while content is being read, so the file
+// never has to be held in memory.
+func newLocalFileBody(fileName string, size int64, content io.Reader) *localFileBody {
+	pr, pw := io.Pipe()
+	body := &localFileBody{PipeReader: pr, done: make(chan struct{})}
+
+	go func() {
+		defer close(body.done)
+
+		name, _ := json.Marshal(fileName)
+		prefix := fmt.Sprintf(`{"local_file_name":%s,"payload":{"total_size_bytes":"%s","content":"`,
+			name, strconv.FormatInt(size, 10))
+		if _, err := io.WriteString(pw, prefix); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+
+		encoder := base64.NewEncoder(base64.StdEncoding, pw)
+		gz := gzip.NewWriter(encoder)
+		if _, err := io.Copy(gz, content); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		if err := gz.Close(); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		if err := encoder.Close(); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+
+		_, err := io.WriteString(pw, `"}}`)
+		pw.CloseWithError(err)
+	}()
+
+	return body
+}
@@ -0,0 +1,642 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/5st7/tidb-cloud-go/pkg/models"
+)
+
+func TestClient_ListImports(t *testing.T) {
+	tests := []struct {
+		name           string
+		projectID      string
+		clusterID      string
+		serverResponse func(w http.ResponseWriter, r *http.Request)
+		expectedCount  int
+		expectedPhase  models.ImportStatusImportTaskPhase
+		expectedError  string
+	}{
+		{
+			name:      "successful list imports",
+			projectID: "test-project",
+			clusterID: "test-cluster",
+			serverResponse: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "GET" {
+					t.Errorf("Expected GET, got %s", r.Method)
+				}
+				if r.URL.Path != "/api/v1beta/projects/test-project/clusters/test-cluster/imports" {
+					t.Errorf("Unexpected path %s", r.URL.Path)
+				}
+
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{
+					"items": [{
+						"metadata": {"id": "1", "name": "weekly_load", "create_timestamp": "1676450597"},
+						"spec": {
+							"source": {
+								"type": "S3",
+								"uri": "s3://example-bucket/data/",
+								"aws_assume_role_access": {"assume_role": "arn:aws:iam::123456789012:role/import"},
+								"format": {"type": "CSV", "csv_config": {"delimiter": ",", "has_header_row": true}}
+							},
+							"target": {"tables": [{"database_name": "db", "table_name": "t", "file_name_pattern": "t.*.csv"}]}
+						},
+						"status": {
+							"phase": "IMPORTING",
+							"progress": {"import_progress": 42.5, "validation_progress": 0},
+							"source_total_size_bytes": "1024"
+						}
+					}],
+					"total": 1
+				}`))
+			},
+			expectedCount: 1,
+			expectedPhase: models.ImportStatusImportTaskPhaseIMPORTING,
+		},
+		{
+			name:      "empty cluster ID",
+			projectID: "test-project",
+			clusterID: "",
+			serverResponse: func(w http.ResponseWriter, r *http.Request) {
+				t.Error("Server should not be called with empty cluster ID")
+			},
+			expectedError: "cluster ID is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(tt.serverResponse))
+			defer server.Close()
+
+			client, err := NewClient("test-key", "test-secret")
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			client.baseURL = server.URL
+
+			resp, err := client.ListImports(context.Background(), tt.projectID, tt.clusterID)
+
+			if tt.expectedError != "" {
+				if err == nil {
+					t.Errorf("Expected error %q, got nil", tt.expectedError)
+				} else if err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got %q", tt.expectedError, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if len(resp.Items) != tt.expectedCount {
+				t.Fatalf("Expected %d imports, got %d", tt.expectedCount, len(resp.Items))
+			}
+
+			item := resp.Items[0]
+			if item.Status == nil || item.Status.Phase == nil || *item.Status.Phase != tt.expectedPhase {
+				t.Errorf("Expected phase %s, got %v", tt.expectedPhase, item.Status)
+			}
+			if *item.Status.Progress.ImportProgress != 42.5 {
+				t.Errorf("Expected import progress 42.5, got %v", *item.Status.Progress.ImportProgress)
+			}
+			if *item.Spec.Source.Format.Type != models.ImportSourceFormatImportSourceFormatTypeCSV {
+				t.Errorf("Expected CSV format, got %v", *item.Spec.Source.Format.Type)
+			}
+			if !*item.Spec.Source.Format.CSVConfig.HasHeaderRow {
+				t.Error("Expected has_header_row to be true")
+			}
+		})
+	}
+}
+
+func TestClient_GetImport(t *testing.T) {
+	tests := []struct {
+		name           string
+		importID       string
+		serverResponse func(w http.ResponseWriter, r *http.Request)
+		expectedError  string
+	}{
+		{
+			name:     "successful get import",
+			importID: "42",
+			serverResponse: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v1beta/projects/test-project/clusters/test-cluster/imports/42" {
+					t.Errorf("Unexpected path %s", r.URL.Path)
+				}
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"metadata": {"id": "42"}, "status": {"phase": "FAILED", "error_message": "bad row"}}`))
+			},
+		},
+		{
+			name:     "empty import ID",
+			importID: "",
+			serverResponse: func(w http.ResponseWriter, r *http.Request) {
+				t.Error("Server should not be called with empty import ID")
+			},
+			expectedError: "import ID is required",
+		},
+		{
+			name:     "import not found",
+			importID: "404",
+			serverResponse: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"code":    49900004,
+					"message": "import task not found",
+				})
+			},
+			expectedError: "failed to execute request: TiDB Cloud API error (404): import task not found (code: 49900004)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(tt.serverResponse))
+			defer server.Close()
+
+			client, err := NewClient("test-key", "test-secret")
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			client.baseURL = server.URL
+
+			item, err := client.GetImport(context.Background(), "test-project", "test-cluster", tt.importID)
+
+			if tt.expectedError != "" {
+				if err == nil {
+					t.Errorf("Expected error %q, got nil", tt.expectedError)
+				} else if err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got %q", tt.expectedError, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if *item.Metadata.ID != tt.importID {
+				t.Errorf("Expected import ID %s, got %s", tt.importID, *item.Metadata.ID)
+			}
+			if *item.Status.Phase != models.ImportStatusImportTaskPhaseFAILED {
+				t.Errorf("Expected phase FAILED, got %s", *item.Status.Phase)
+			}
+			if *item.Status.ErrorMessage != "bad row" {
+				t.Errorf("Expected error message %q, got %q", "bad row", *item.Status.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestClient_CreateImport(t *testing.T) {
+	sourceType := models.ImportSourceImportSourceTypeGCS
+	formatType := models.ImportSourceFormatImportSourceFormatTypePARQUET
+
+	tests := []struct {
+		name           string
+		req            *models.OpenapiCreateImportTaskReq
+		serverResponse func(w http.ResponseWriter, r *http.Request)
+		expectedID     string
+		expectedError  string
+	}{
+		{
+			name: "successful create import",
+			req: &models.OpenapiCreateImportTaskReq{
+				Name: stringPtr("weekly_load"),
+				Spec: &models.OpenapiImportSpec{
+					Source: &models.OpenapiImportSource{
+						Type:   &sourceType,
+						URI:    stringPtr("gs://example-bucket/data/"),
+						Format: &models.OpenapiImportSourceFormat{Type: &formatType},
+					},
+				},
+			},
+			serverResponse: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "POST" {
+					t.Errorf("Expected POST, got %s", r.Method)
+				}
+
+				var req models.OpenapiCreateImportTaskReq
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("Failed to decode request body: %v", err)
+				}
+				if req.Spec == nil || req.Spec.Source == nil || *req.Spec.Source.Type != models.ImportSourceImportSourceTypeGCS {
+					t.Errorf("Expected GCS source, got %+v", req.Spec)
+				}
+
+				w.WriteHeader(http.StatusOK)
+				json.NewEncoder(w).Encode(&models.OpenapiCreateImportTaskResp{ID: stringPtr("12345")})
+			},
+			expectedID: "12345",
+		},
+		{
+			name: "nil request",
+			req:  nil,
+			serverResponse: func(w http.ResponseWriter, r *http.Request) {
+				t.Error("Server should not be called with nil request")
+			},
+			expectedError: "request is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(tt.serverResponse))
+			defer server.Close()
+
+			client, err := NewClient("test-key", "test-secret")
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			client.baseURL = server.URL
+
+			resp, err := client.CreateImport(context.Background(), "test-project", "test-cluster", tt.req)
+
+			if tt.expectedError != "" {
+				if err == nil {
+					t.Errorf("Expected error %q, got nil", tt.expectedError)
+				} else if err.Error() != tt.expectedError {
+					t.Errorf("Expected error %q, got %q", tt.expectedError, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if resp.ID == nil || *resp.ID != tt.expectedID {
+				t.Errorf("Expected import ID %s, got %v", tt.expectedID, resp.ID)
+			}
+		})
+	}
+}
+
+func TestClient_CancelImport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" {
+			t.Errorf("Expected PATCH, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/v1beta/projects/test-project/clusters/test-cluster/imports/42" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+
+		var req models.OpenapiUpdateImportTaskReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if req.Action == nil || *req.Action != models.UpdateImportTaskReqImportTaskActionCANCEL {
+			t.Errorf("Expected CANCEL action, got %v", req.Action)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", "test-secret")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.baseURL = server.URL
+
+	if err := client.CancelImport(context.Background(), "test-project", "test-cluster", "42"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if err := client.CancelImport(context.Background(), "test-project", "test-cluster", ""); err == nil {
+		t.Error("Expected error for empty import ID, got nil")
+	}
+}
+
+func TestClient_UploadImportFile(t *testing.T) {
+	const content = "id,name\n1,alice\n2,bob\n"
+
+	path := filepath.Join(t.TempDir(), "users.csv")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		fileName string
+		reader   func(t *testing.T) io.Reader
+	}{
+		{
+			name:     "streams seekable file",
+			fileName: path,
+			reader: func(t *testing.T) io.Reader {
+				f, err := os.Open(path)
+				if err != nil {
+					t.Fatalf("Failed to open test file: %v", err)
+				}
+				t.Cleanup(func() { f.Close() })
+				return f
+			},
+		},
+		{
+			name:     "buffers non-seekable reader",
+			fileName: "users.csv",
+			reader: func(t *testing.T) io.Reader {
+				return io.MultiReader(strings.NewReader(content))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v1beta/projects/test-project/clusters/test-cluster/imports/upload_file" {
+					t.Errorf("Unexpected path %s", r.URL.Path)
+				}
+
+				// Force the digest handshake so the body has to be sent twice
+				if r.Header.Get("Authorization") == "" {
+					io.Copy(io.Discard, r.Body)
+					w.Header().Set("WWW-Authenticate", `Digest realm="tidbcloud", nonce="test123", qop="auth"`)
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				var req models.OpenapiUploadLocalFileReq
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatalf("Failed to decode request body: %v", err)
+				}
+				if *req.LocalFileName != "users.csv" {
+					t.Errorf("Expected file name users.csv, got %s", *req.LocalFileName)
+				}
+				if want := strconv.Itoa(len(content)); *req.Payload.TotalSizeBytes != want {
+					t.Errorf("Expected total size %s, got %s", want, *req.Payload.TotalSizeBytes)
+				}
+
+				compressed, err := base64.StdEncoding.DecodeString(*req.Payload.Content)
+				if err != nil {
+					t.Fatalf("Failed to decode base64 content: %v", err)
+				}
+				gz, err := gzip.NewReader(bytes.NewReader(compressed))
+				if err != nil {
+					t.Fatalf("Failed to open gzip content: %v", err)
+				}
+				raw, err := io.ReadAll(gz)
+				if err != nil {
+					t.Fatalf("Failed to decompress content: %v", err)
+				}
+				if string(raw) != content {
+					t.Errorf("Expected content %q, got %q", content, string(raw))
+				}
+
+				w.WriteHeader(http.StatusOK)
+				json.NewEncoder(w).Encode(&models.OpenapiUploadLocalFileResp{UploadStubID: stringPtr("123")})
+			}))
+			defer server.Close()
+
+			client, err := NewClient("test-key", "test-secret")
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			client.baseURL = server.URL
+
+			resp, err := client.UploadImportFile(context.Background(), "test-project", "test-cluster", tt.fileName, tt.reader(t))
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if resp.UploadStubID == nil || *resp.UploadStubID != "123" {
+				t.Errorf("Expected upload stub ID 123, got %v", resp.UploadStubID)
+			}
+		})
+	}
+}
+
+// exclusiveReadSeeker fails the test if it is read or rewound by two
+// goroutines at once.
+type exclusiveReadSeeker struct {
+	t     *testing.T
+	r     io.ReadSeeker
+	inUse atomic.Bool
+}
+
+func (e *exclusiveReadSeeker) Read(p []byte) (int, error) {
+	if !e.inUse.CompareAndSwap(false, true) {
+		e.t.Error("Content read while still in use by another attempt")
+		return 0, io.ErrUnexpectedEOF
+	}
+	defer e.inUse.Store(false)
+	return e.r.Read(p)
+}
+
+func (e *exclusiveReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	if !e.inUse.CompareAndSwap(false, true) {
+		e.t.Error("Content rewound while still in use by another attempt")
+		return 0, io.ErrUnexpectedEOF
+	}
+	defer e.inUse.Store(false)
+	return e.r.Seek(offset, whence)
+}
+
+func TestClient_UploadImportFile_Resend(t *testing.T) {
+	// Incompressible content keeps the first attempt streaming when it is rejected
+	content := make([]byte, 4<<20)
+	rand.New(rand.NewSource(1)).Read(content)
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		// Reject the first attempt before reading its body, then keep reading
+		// it so the client is still sending it when the request is resent
+		if r.Header.Get("Authorization") == "" {
+			rc := http.NewResponseController(w)
+			rc.EnableFullDuplex()
+			w.Header().Set("WWW-Authenticate", `Digest realm="tidbcloud", nonce="test123", qop="auth"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			rc.Flush()
+			io.Copy(io.Discard, r.Body)
+			return
+		}
+
+		var req models.OpenapiUploadLocalFileReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		compressed, err := base64.StdEncoding.DecodeString(*req.Payload.Content)
+		if err != nil {
+			t.Fatalf("Failed to decode base64 content: %v", err)
+		}
+		gz, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			t.Fatalf("Failed to open gzip content: %v", err)
+		}
+		raw, err := io.ReadAll(gz)
+		if err != nil {
+			t.Fatalf("Failed to decompress content: %v", err)
+		}
+		if !bytes.Equal(raw, content) {
+			t.Errorf("Expected the resent content to match the file, got %d bytes", len(raw))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(&models.OpenapiUploadLocalFileResp{UploadStubID: stringPtr("123")})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", "test-secret")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.baseURL = server.URL
+
+	reader := &exclusiveReadSeeker{t: t, r: bytes.NewReader(content)}
+	resp, err := client.UploadImportFile(context.Background(), "test-project", "test-cluster", "data.csv", reader)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.UploadStubID == nil || *resp.UploadStubID != "123" {
+		t.Errorf("Expected upload stub ID 123, got %v", resp.UploadStubID)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("Expected 2 attempts, got %d", got)
+	}
+}
+
+func TestLocalFileBody_Close(t *testing.T) {
+	content := &blockingReader{started: make(chan struct{}), release: make(chan struct{})}
+	body := newLocalFileBody("data.csv", 1, content)
+	go io.Copy(io.Discard, body)
+	<-content.started
+
+	// Close must not return while the content is still being read
+	closed := make(chan struct{})
+	go func() {
+		body.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Fatal("Close() returned while the content was being read")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(content.release)
+	<-closed
+}
+
+// blockingReader blocks its first Read until released.
+type blockingReader struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingReader) Read(p []byte) (int, error) {
+	close(b.started)
+	<-b.release
+	return 0, io.EOF
+}
+
+func TestClient_UploadImportFile_TooLarge(t *testing.T) {
+	client, err := NewClient("test-key", "test-secret")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	large := bytes.NewReader(make([]byte, MaxLocalFileSize+1))
+	_, err = client.UploadImportFile(context.Background(), "test-project", "test-cluster", "large.csv", large)
+	if err == nil {
+		t.Fatal("Expected error for file above the maximum size, got nil")
+	}
+}
+
+func TestClient_PreviewImport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/v1beta/projects/test-project/clusters/test-cluster/imports/preview" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+
+		var req models.OpenapiPreviewImportDataReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if req.LimitRowsCount == nil || *req.LimitRowsCount != 5 {
+			t.Errorf("Expected limit_rows_count 5, got %v", req.LimitRowsCount)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"table_previews": [{
+				"database_name": "db",
+				"table_name": "users",
+				"schema_preview": {
+					"column_definitions": [
+						{"column_name": "id", "column_type": "INT"},
+						{"column_name": "name", "column_type": "VARCHAR(255)"}
+					],
+					"primary_key_columns": ["id"]
+				},
+				"data_preview": {
+					"column_names": ["id", "name"],
+					"rows": [{"columns": ["1", "alice"]}, {"columns": ["2", "bob"]}]
+				}
+			}]
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-key", "test-secret")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.baseURL = server.URL
+
+	sourceType := models.ImportSourceImportSourceTypeLOCALFILE
+	req := &models.OpenapiPreviewImportDataReq{
+		Spec: &models.OpenapiImportSpec{
+			Source: &models.OpenapiImportSource{
+				Type: &sourceType,
+				URI:  stringPtr("file://123/"),
+			},
+		},
+		LimitRowsCount: int64Ptr(5),
+	}
+
+	resp, err := client.PreviewImport(context.Background(), "test-project", "test-cluster", req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(resp.TablePreviews) != 1 {
+		t.Fatalf("Expected 1 table preview, got %d", len(resp.TablePreviews))
+	}
+	preview := resp.TablePreviews[0]
+	if len(preview.SchemaPreview.ColumnDefinitions) != 2 {
+		t.Errorf("Expected 2 columns, got %d", len(preview.SchemaPreview.ColumnDefinitions))
+	}
+	if *preview.SchemaPreview.ColumnDefinitions[1].ColumnType != "VARCHAR(255)" {
+		t.Errorf("Expected VARCHAR(255), got %s", *preview.SchemaPreview.ColumnDefinitions[1].ColumnType)
+	}
+	if len(preview.DataPreview.Rows) != 2 || preview.DataPreview.Rows[1].Columns[1] != "bob" {
+		t.Errorf("Unexpected data preview rows: %+v", preview.DataPreview.Rows)
+	}
+
+	if _, err := client.PreviewImport(context.Background(), "test-project", "test-cluster", nil); err == nil {
+		t.Error("Expected error for nil request, got nil")
+	}
+}
@@ -0,0 +1,96 @@
+package client
+
+// Operation names an API operation of the client, e.g. to give it its own
+// retry policy with WithOperationRetryPolicy. The names match the Client
+// methods; context and options variants share the operation of their method.
+type Operation string
+
+// Project operations.
+const (
+	OpListProjects  Operation = "ListProjects"
+	OpCreateProject Operation = "CreateProject"
+)
+
+// Cluster operations.
+const (
+	OpListClusters  Operation = "ListClusters"
+	OpGetCluster    Operation = "GetCluster"
+	OpCreateCluster Operation = "CreateCluster"
+	OpUpdateCluster Operation = "UpdateCluster"
+	OpDeleteCluster Operation = "DeleteCluster"
+)
+
+// Backup and restore operations.
+const (
+	OpListBackups   Operation = "ListBackups"
+	OpGetBackup     Operation = "GetBackup"
+	OpCreateBackup  Operation = "CreateBackup"
+	OpDeleteBackup  Operation = "DeleteBackup"
+	OpListRestores  Operation = "ListRestores"
+	OpGetRestore    Operation = "GetRestore"
+	OpCreateRestore Operation = "CreateRestore"
+)
+
+// Import operations.
+const (
+	OpGetImportRoleInfo Operation = "GetImportRoleInfo"
+	OpListImports       Operation = "ListImports"
+	OpGetImport         Operation = "GetImport"
+	OpCreateImport      Operation = "CreateImport"
+	OpCancelImport      Operation = "CancelImport"
+	OpUploadImportFile  Operation = "UploadImportFile"
+	OpPreviewImport     Operation = "PreviewImport"
+)
+
+// Private endpoint operations.
+const (
+	OpGetPrivateEndpointService     Operation = "GetPrivateEndpointService"
+	OpCreatePrivateEndpointService  Operation = "CreatePrivateEndpointService"
+	OpListPrivateEndpoints          Operation = "ListPrivateEndpoints"
+	OpCreatePrivateEndpoint         Operation = "CreatePrivateEndpoint"
+	OpDeletePrivateEndpoint         Operation = "DeletePrivateEndpoint"
+	OpListPrivateEndpointsOfProject Operation = "ListPrivateEndpointsOfProject"
+)
+
+// Other operations.
+const (
+	OpListAWSCMEK         Operation = "ListAWSCMEK"
+	OpCreateAWSCMEK       Operation = "CreateAWSCMEK"
+	OpListProviderRegions Operation = "ListProviderRegions"
+)
+
+// Idempotency describes whether an operation can safely be sent again after
+// a failure that the server may already have acted on, such as a timeout or
+// a 502 after the request was received.
+type Idempotency int
+
+const (
+	// Idempotent operations have the same effect however often they are sent,
+	// so they are retried after any retryable error.
+	Idempotent Idempotency = iota
+	// IdempotentDelete operations are retried like Idempotent ones, but a 404
+	// on a retry means an earlier attempt deleted the resource and is success.
+	IdempotentDelete
+	// NonIdempotent operations may create a resource each time they are sent.
+	// After a failure the server may have acted on, they are retried only if
+	// a lookup finds that the earlier attempt had no effect; operations that
+	// have no such lookup are not retried then. Rate limits (429) and 503
+	// errors are retried as usual, since the request was not processed.
+	NonIdempotent
+)
+
+// Idempotency classifies op. CreateCluster, CreateBackup, CreateRestore and
+// CreatePrivateEndpoint look up the resource by name before a retry and
+// return it if the earlier attempt created it.
+func (op Operation) Idempotency() Idempotency {
+	switch op {
+	case OpDeleteCluster, OpDeleteBackup, OpDeletePrivateEndpoint:
+		return IdempotentDelete
+	case OpCreateProject, OpCreateCluster, OpCreateBackup, OpCreateRestore,
+		OpCreateImport, OpUploadImportFile, OpCreateAWSCMEK,
+		OpCreatePrivateEndpointService, OpCreatePrivateEndpoint:
+		return NonIdempotent
+	default:
+		return Idempotent
+	}
+}
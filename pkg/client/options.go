@@ -0,0 +1,174 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/5st7/tidb-cloud-go/pkg/auth"
+	"github.com/5st7/tidb-cloud-go/pkg/errors"
+	"github.com/5st7/tidb-cloud-go/pkg/ratelimit"
+	"github.com/5st7/tidb-cloud-go/pkg/retry"
+)
+
+const (
+	// DefaultTimeout is the HTTP timeout used when no HTTP client or timeout is configured
+	DefaultTimeout = 30 * time.Second
+	// DefaultUserAgent is the User-Agent header sent with every request
+	DefaultUserAgent = "tidb-cloud-go"
+)
+
+// Option configures a Client created by NewClient.
+type Option func(*Client) error
+
+// WithBaseURL sets the API base URL, e.g. a staging endpoint or a local mock
+// server. The URL must be absolute; a trailing slash is ignored.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("invalid base URL: %w", err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return errors.Invalid("base URL", "invalid base URL %q: scheme and host are required", baseURL)
+		}
+		c.baseURL = strings.TrimRight(baseURL, "/")
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests, e.g. one with a
+// proxy-configured transport. The client is not modified; if WithTimeout is
+// also given, a copy carrying that timeout is used instead.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) error {
+		if httpClient == nil {
+			return errors.Required("HTTP client")
+		}
+		c.httpClient = httpClient
+		return nil
+	}
+}
+
+// WithTimeout sets the timeout of each HTTP request. It overrides the timeout
+// of a client given with WithHTTPClient, regardless of option order.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		if timeout <= 0 {
+			return errors.Invalid("timeout", "timeout must be positive")
+		}
+		c.timeout = timeout
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the retry policy applied to every request, except for
+// operations given their own policy with WithOperationRetryPolicy.
+func WithRetryPolicy(policy *retry.RetryPolicy) Option {
+	return func(c *Client) error {
+		if policy == nil {
+			return errors.Required("retry policy")
+		}
+		c.retryExecutor = retry.NewRetryExecutor(policy)
+		return nil
+	}
+}
+
+// WithOperationRetryPolicy sets the retry policy of a single operation, e.g.
+// more attempts for OpGetCluster, or a MaxAttempts of zero to never retry
+// OpCreateCluster. Other operations keep the policy set by WithRetryPolicy.
+func WithOperationRetryPolicy(op Operation, policy *retry.RetryPolicy) Option {
+	return func(c *Client) error {
+		if policy == nil {
+			return errors.Required("retry policy")
+		}
+		if c.operationRetry == nil {
+			c.operationRetry = make(map[Operation]*retry.RetryExecutor)
+		}
+		c.operationRetry[op] = retry.NewRetryExecutor(policy)
+		return nil
+	}
+}
+
+// WithCircuitBreaker sends every request of the client through breaker, which
+// fails fast with retry.ErrCircuitOpen while the API is degraded instead of
+// retrying. Share one breaker between clients so that they trip together;
+// breaker.State() reports its state for health checks.
+func WithCircuitBreaker(breaker *retry.CircuitBreaker) Option {
+	return func(c *Client) error {
+		if breaker == nil {
+			return errors.Required("circuit breaker")
+		}
+		c.circuitBreaker = breaker
+		return nil
+	}
+}
+
+// WithRateLimiter throttles every request of the client, including digest
+// challenge resends and retries, through l, e.g. ratelimit.NewDefaultLimiter()
+// to stay under the quota of 100 requests per minute. Pass
+// ratelimit.ForKey(publicKey) to share the quota with other clients using the
+// same API key. Requests are not rate limited by default.
+func WithRateLimiter(l *ratelimit.Limiter) Option {
+	return func(c *Client) error {
+		if l == nil {
+			return errors.Required("rate limiter")
+		}
+		c.rateLimiter = l
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) error {
+		if userAgent == "" {
+			return errors.Required("user agent")
+		}
+		c.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithAPIVersion sets the API version used in request paths, replacing APIVersion.
+func WithAPIVersion(version string) Option {
+	return func(c *Client) error {
+		if version == "" || strings.Contains(version, "/") {
+			return errors.Invalid("API version", "invalid API version %q", version)
+		}
+		c.apiVersion = version
+		return nil
+	}
+}
+
+// WithAuthenticator replaces the default HTTP Digest authentication with a, e.g.
+// an auth.BearerAuthenticator or an auth.AuthenticatorFunc that signs requests
+// for a gateway.
+func WithAuthenticator(a auth.Authenticator) Option {
+	return func(c *Client) error {
+		if a == nil {
+			return errors.Required("authenticator")
+		}
+		c.authenticator = a
+		return nil
+	}
+}
+
+// WithCredentialsProvider authenticates with HTTP Digest Authentication using
+// keys from provider, which is consulted for every request through an
+// auth.CredentialsCache, so rotated keys are picked up without recreating the
+// client. The key pair passed to NewClient is not used and may be empty.
+func WithCredentialsProvider(provider auth.CredentialsProvider) Option {
+	return func(c *Client) error {
+		if provider == nil {
+			return errors.Required("credentials provider")
+		}
+		if _, ok := provider.(*auth.CredentialsCache); !ok {
+			provider = auth.NewCredentialsCache(provider, 0)
+		}
+		c.authenticator = auth.NewDigestAuthenticatorFromProvider(provider)
+		return nil
+	}
+}
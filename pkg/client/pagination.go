@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+// DefaultPageSize is the page size used by the All* iterators.
+const DefaultPageSize = 100
+
+// ListOptions selects a page of a list endpoint.
+// Zero values are omitted, so the server defaults apply (page 1, 10 items).
+type ListOptions struct {
+	// Page is the 1-based page number
+	Page int64
+	// PageSize is the number of items per page
+	PageSize int64
+}
+
+// withListOptions appends the page and page_size query parameters to endpoint.
+func withListOptions(endpoint string, opts *ListOptions) string {
+	if opts == nil {
+		return endpoint
+	}
+
+	query := url.Values{}
+	if opts.Page > 0 {
+		query.Set("page", strconv.FormatInt(opts.Page, 10))
+	}
+	if opts.PageSize > 0 {
+		query.Set("page_size", strconv.FormatInt(opts.PageSize, 10))
+	}
+	if len(query) == 0 {
+		return endpoint
+	}
+
+	return endpoint + "?" + query.Encode()
+}
+
+// paginate walks every page of a list endpoint, yielding items one by one.
+// fetch returns the items of a page and the total item count reported by the
+// server, or -1 if the server did not report it. Iteration stops after total
+// items (or a short page when the total is unknown), on an empty page, on the
+// first error (yielded with a zero item), or when the consumer stops ranging.
+func paginate[T any](ctx context.Context, fetch func(ctx context.Context, opts *ListOptions) ([]T, int64, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var seen int64
+		for page := int64(1); ; page++ {
+			items, total, err := fetch(ctx, &ListOptions{Page: page, PageSize: DefaultPageSize})
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			seen += int64(len(items))
+			if len(items) == 0 {
+				return
+			}
+			if total >= 0 && seen >= total {
+				return
+			}
+			if total < 0 && len(items) < DefaultPageSize {
+				return
+			}
+		}
+	}
+}
+
+func totalValue(total *int64) int64 {
+	if total == nil {
+		return -1
+	}
+	return *total
+}
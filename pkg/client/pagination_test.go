@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/5st7/tidb-cloud-go/pkg/models"
+)
+
+func TestWithListOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     *ListOptions
+		expected string
+	}{
+		{name: "nil options", opts: nil, expected: "https://example.com/items"},
+		{name: "zero options", opts: &ListOptions{}, expected: "https://example.com/items"},
+		{name: "page only", opts: &ListOptions{Page: 3}, expected: "https://example.com/items?page=3"},
+		{name: "page and size", opts: &ListOptions{Page: 2, PageSize: 50}, expected: "https://example.com/items?page=2&page_size=50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withListOptions("https://example.com/items", tt.opts); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func newProjectPagesServer(t *testing.T, total int, reportTotal bool, requests *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.URL.RawQuery)
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+		if page < 1 || pageSize < 1 {
+			t.Errorf("Expected page and page_size query parameters, got %q", r.URL.RawQuery)
+		}
+
+		var resp models.OpenapiListProjectsResp
+		for i := (page - 1) * pageSize; i < total && i < page*pageSize; i++ {
+			resp.Items = append(resp.Items, &models.OpenapiListProjectItem{ID: stringPtr(fmt.Sprintf("p%d", i))})
+		}
+		if reportTotal {
+			resp.Total = int64Ptr(int64(total))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(&resp)
+	}))
+}
+
+func TestClient_AllProjects(t *testing.T) {
+	tests := []struct {
+		name             string
+		total            int
+		reportTotal      bool
+		expectedRequests int
+	}{
+		{name: "multiple pages with total", total: 250, reportTotal: true, expectedRequests: 3},
+		{name: "exact page multiple with total", total: 200, reportTotal: true, expectedRequests: 2},
+		{name: "without total stops on short page", total: 150, reportTotal: false, expectedRequests: 2},
+		{name: "without total stops on empty page", total: 200, reportTotal: false, expectedRequests: 3},
+		{name: "empty", total: 0, reportTotal: true, expectedRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			server := newProjectPagesServer(t, tt.total, tt.reportTotal, &requests)
+			defer server.Close()
+
+			client, err := NewClient("test_public", "test_private")
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			client.baseURL = server.URL
+
+			count := 0
+			for project, err := range client.AllProjects(context.Background()) {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if expected := fmt.Sprintf("p%d", count); *project.ID != expected {
+					t.Errorf("Expected project %s, got %s", expected, *project.ID)
+				}
+				count++
+			}
+
+			if count != tt.total {
+				t.Errorf("Expected %d projects, got %d", tt.total, count)
+			}
+			if len(requests) != tt.expectedRequests {
+				t.Errorf("Expected %d requests, got %d: %v", tt.expectedRequests, len(requests), requests)
+			}
+		})
+	}
+}
+
+func TestClient_AllProjects_EarlyBreak(t *testing.T) {
+	var requests []string
+	server := newProjectPagesServer(t, 500, true, &requests)
+	defer server.Close()
+
+	client, err := NewClient("test_public", "test_private")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.baseURL = server.URL
+
+	count := 0
+	for _, err := range client.AllProjects(context.Background()) {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		count++
+		if count == 120 {
+			break
+		}
+	}
+
+	if len(requests) != 2 {
+		t.Errorf("Expected 2 requests, got %d", len(requests))
+	}
+}
+
+func TestClient_AllBackups_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test_public", "test_private")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.baseURL = server.URL
+
+	var errs int
+	for backup, err := range client.AllBackups(context.Background(), "project123", "cluster456") {
+		if err == nil {
+			t.Fatalf("Expected error, got backup %+v", backup)
+		}
+		errs++
+	}
+	if errs != 1 {
+		t.Errorf("Expected a single error, got %d", errs)
+	}
+
+	for _, err := range client.AllBackups(context.Background(), "", "cluster456") {
+		if err == nil || err.Error() != "project ID is required" {
+			t.Errorf("Expected project ID error, got %v", err)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/5st7/tidb-cloud-go/pkg/errors"
 	"github.com/5st7/tidb-cloud-go/pkg/models"
 )
 
@@ -24,20 +25,20 @@ import (
 //   - error: An error if the request fails or parameters are invalid
 func (c *Client) GetPrivateEndpointService(ctx context.Context, projectID, clusterID string) (*models.OpenapiGetPrivateEndpointServiceResp, error) {
 	if projectID == "" {
-		return nil, fmt.Errorf("project ID is required")
+		return nil, errors.Required("project ID")
 	}
 	if clusterID == "" {
-		return nil, fmt.Errorf("cluster ID is required")
+		return nil, errors.Required("cluster ID")
 	}
 
-	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/private_endpoint_service", c.baseURL, APIVersion, projectID, clusterID)
+	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/private_endpoint_service", c.baseURL, c.apiVersion, projectID, clusterID)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.doRequestWithRetry(ctx, req)
+	resp, err := c.doRequestWithRetry(ctx, OpGetPrivateEndpointService, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -69,13 +70,13 @@ func (c *Client) GetPrivateEndpointService(ctx context.Context, projectID, clust
 //   - error: An error if the request fails or parameters are invalid
 func (c *Client) CreatePrivateEndpointService(ctx context.Context, projectID, clusterID string) (*models.OpenapiGetPrivateEndpointServiceResp, error) {
 	if projectID == "" {
-		return nil, fmt.Errorf("project ID is required")
+		return nil, errors.Required("project ID")
 	}
 	if clusterID == "" {
-		return nil, fmt.Errorf("cluster ID is required")
+		return nil, errors.Required("cluster ID")
 	}
 
-	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/private_endpoint_service", c.baseURL, APIVersion, projectID, clusterID)
+	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/private_endpoint_service", c.baseURL, c.apiVersion, projectID, clusterID)
 
 	// According to the API spec, the body should be an empty object
 	reqBody := map[string]interface{}{}
@@ -90,7 +91,7 @@ func (c *Client) CreatePrivateEndpointService(ctx context.Context, projectID, cl
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.doRequestWithRetry(ctx, req)
+	resp, err := c.doRequestWithRetry(ctx, OpCreatePrivateEndpointService, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -121,20 +122,20 @@ func (c *Client) CreatePrivateEndpointService(ctx context.Context, projectID, cl
 //   - error: An error if the request fails or parameters are invalid
 func (c *Client) ListPrivateEndpoints(ctx context.Context, projectID, clusterID string) (*models.OpenapiListPrivateEndpointsResp, error) {
 	if projectID == "" {
-		return nil, fmt.Errorf("project ID is required")
+		return nil, errors.Required("project ID")
 	}
 	if clusterID == "" {
-		return nil, fmt.Errorf("cluster ID is required")
+		return nil, errors.Required("cluster ID")
 	}
 
-	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/private_endpoints", c.baseURL, APIVersion, projectID, clusterID)
+	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/private_endpoints", c.baseURL, c.apiVersion, projectID, clusterID)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.doRequestWithRetry(ctx, req)
+	resp, err := c.doRequestWithRetry(ctx, OpListPrivateEndpoints, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -167,16 +168,16 @@ func (c *Client) ListPrivateEndpoints(ctx context.Context, projectID, clusterID
 //   - error: An error if the request fails or parameters are invalid
 func (c *Client) CreatePrivateEndpoint(ctx context.Context, projectID, clusterID string, req *models.OpenapiCreatePrivateEndpointReq) (*models.OpenapiCreatePrivateEndpointResp, error) {
 	if projectID == "" {
-		return nil, fmt.Errorf("project ID is required")
+		return nil, errors.Required("project ID")
 	}
 	if clusterID == "" {
-		return nil, fmt.Errorf("cluster ID is required")
+		return nil, errors.Required("cluster ID")
 	}
 	if req == nil {
-		return nil, fmt.Errorf("request is required")
+		return nil, errors.Required("request")
 	}
 
-	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/private_endpoints", c.baseURL, APIVersion, projectID, clusterID)
+	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/private_endpoints", c.baseURL, c.apiVersion, projectID, clusterID)
 
 	reqBody, err := json.Marshal(req)
 	if err != nil {
@@ -189,10 +190,24 @@ func (c *Client) CreatePrivateEndpoint(ctx context.Context, projectID, clusterID
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.doRequestWithRetry(ctx, httpReq)
+	// Before resending, look for the endpoint in case an earlier attempt created it
+	var created *models.OpenapiCreatePrivateEndpointResp
+	var reconcile reconcileFunc
+	if req.EndpointName != nil {
+		reconcile = func(ctx context.Context) (bool, error) {
+			var err error
+			created, err = c.findCreatedPrivateEndpoint(ctx, projectID, clusterID, req)
+			return created != nil, err
+		}
+	}
+
+	resp, reconciled, err := c.doRequestWithReconcile(ctx, OpCreatePrivateEndpoint, httpReq, reconcile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
+	if reconciled {
+		return created, nil
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
@@ -221,26 +236,29 @@ func (c *Client) CreatePrivateEndpoint(ctx context.Context, projectID, clusterID
 //   - error: An error if the request fails or parameters are invalid
 func (c *Client) DeletePrivateEndpoint(ctx context.Context, projectID, clusterID, endpointID string) error {
 	if projectID == "" {
-		return fmt.Errorf("project ID is required")
+		return errors.Required("project ID")
 	}
 	if clusterID == "" {
-		return fmt.Errorf("cluster ID is required")
+		return errors.Required("cluster ID")
 	}
 	if endpointID == "" {
-		return fmt.Errorf("endpoint ID is required")
+		return errors.Required("endpoint ID")
 	}
 
-	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/private_endpoints/%s", c.baseURL, APIVersion, projectID, clusterID, endpointID)
+	url := fmt.Sprintf("%s/api/%s/projects/%s/clusters/%s/private_endpoints/%s", c.baseURL, c.apiVersion, projectID, clusterID, endpointID)
 
 	req, err := http.NewRequest("DELETE", url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.doRequestWithRetry(ctx, req)
+	resp, reconciled, err := c.doRequestWithReconcile(ctx, OpDeletePrivateEndpoint, req, nil)
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
 	}
+	if reconciled {
+		return nil
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
@@ -263,17 +281,17 @@ func (c *Client) DeletePrivateEndpoint(ctx context.Context, projectID, clusterID
 //   - error: An error if the request fails or parameters are invalid
 func (c *Client) ListPrivateEndpointsOfProject(ctx context.Context, projectID string) (*models.OpenapiListPrivateEndpointsResp, error) {
 	if projectID == "" {
-		return nil, fmt.Errorf("project ID is required")
+		return nil, errors.Required("project ID")
 	}
 
-	url := fmt.Sprintf("%s/api/%s/projects/%s/private_endpoints", c.baseURL, APIVersion, projectID)
+	url := fmt.Sprintf("%s/api/%s/projects/%s/private_endpoints", c.baseURL, c.apiVersion, projectID)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.doRequestWithRetry(ctx, req)
+	resp, err := c.doRequestWithRetry(ctx, OpListPrivateEndpointsOfProject, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
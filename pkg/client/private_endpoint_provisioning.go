@@ -0,0 +1,243 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+
+	"github.com/5st7/tidb-cloud-go/pkg/errors"
+	"github.com/5st7/tidb-cloud-go/pkg/models"
+)
+
+// PrivateEndpointSpec describes the private endpoint EnsurePrivateEndpoint provisions.
+type PrivateEndpointSpec struct {
+	// EndpointName is the name of the cloud-side endpoint, e.g. "vpce-xxxx"
+	// for AWS. If empty, CreateCloudEndpoint is called to create it.
+	EndpointName string
+	// CreateCloudEndpoint creates the cloud-side endpoint, e.g. an AWS
+	// interface VPC endpoint, for the service once it is ACTIVE, and returns
+	// its name. It is only called when EndpointName is empty.
+	CreateCloudEndpoint func(ctx context.Context, service *models.OpenapiPrivateEndpointService) (string, error)
+	// Wait configures polling between the stages; nil uses the defaults. Its
+	// timeout bounds the whole provisioning.
+	Wait *WaitOptions
+}
+
+// PrivateEndpointConnection is the outcome of a successful EnsurePrivateEndpoint.
+type PrivateEndpointConnection struct {
+	// Service is the ACTIVE private endpoint service of the cluster
+	Service *models.OpenapiPrivateEndpointService
+	// Endpoint is the ACTIVE private endpoint
+	Endpoint *models.OpenapiPrivateEndpointItem
+	// DNSName is the host to connect to through the endpoint
+	DNSName string
+	// Port is the port to connect to through the endpoint
+	Port int64
+}
+
+// PrivateEndpointFailedError is returned by EnsurePrivateEndpoint when the
+// private endpoint service or the private endpoint enters a status it does
+// not leave on its own, such as FAILED, instead of ACTIVE.
+type PrivateEndpointFailedError struct {
+	ProjectID    string
+	ClusterID    string
+	EndpointName string // Empty if the service failed
+	Status       string
+	Message      string
+}
+
+// Error implements the error interface.
+func (e *PrivateEndpointFailedError) Error() string {
+	if e.EndpointName == "" {
+		return fmt.Sprintf("private endpoint service of cluster %s entered status %s", e.ClusterID, e.Status)
+	}
+	if e.Message != "" {
+		return fmt.Sprintf("private endpoint %s of cluster %s entered status %s: %s", e.EndpointName, e.ClusterID, e.Status, e.Message)
+	}
+	return fmt.Sprintf("private endpoint %s of cluster %s entered status %s", e.EndpointName, e.ClusterID, e.Status)
+}
+
+// EnsurePrivateEndpoint provisions a private endpoint connection to a cluster
+// and waits until it can be used:
+//  1. It creates the private endpoint service of the cluster unless one
+//     exists, and waits until the service is ACTIVE.
+//  2. It calls spec.CreateCloudEndpoint if spec.EndpointName is empty.
+//  3. It registers the endpoint with CreatePrivateEndpoint unless
+//     ListPrivateEndpoints already reports an endpoint of that name, and
+//     waits until the endpoint is ACTIVE.
+//
+// Existing resources are reused, so EnsurePrivateEndpoint can be called again
+// after a failure or on every deployment. Note: private endpoints are only
+// available for TiDB Cloud Dedicated clusters.
+//
+// Parameters:
+//   - ctx: Context bounding the provisioning
+//   - projectID: The ID of the project containing the cluster
+//   - clusterID: The ID of the cluster
+//   - spec: The endpoint to provision
+//
+// Returns:
+//   - *PrivateEndpointConnection: The service and endpoint, and the DNS name
+//     and port to connect to
+//   - error: A *PrivateEndpointFailedError if the service or endpoint enters
+//     a failure state, or an error if a request fails or the wait times out
+func (c *Client) EnsurePrivateEndpoint(ctx context.Context, projectID, clusterID string, spec *PrivateEndpointSpec) (*PrivateEndpointConnection, error) {
+	if projectID == "" {
+		return nil, errors.Required("project ID")
+	}
+	if clusterID == "" {
+		return nil, errors.Required("cluster ID")
+	}
+	if spec == nil {
+		return nil, errors.Required("spec")
+	}
+	if spec.EndpointName == "" && spec.CreateCloudEndpoint == nil {
+		return nil, errors.Invalid("endpoint name", "endpoint name or CreateCloudEndpoint is required")
+	}
+
+	ctx, opts, cancel := stageWaitOptions(ctx, spec.Wait)
+	defer cancel()
+
+	service, err := c.ensurePrivateEndpointService(ctx, projectID, clusterID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	endpointName := spec.EndpointName
+	if endpointName == "" {
+		endpointName, err = spec.CreateCloudEndpoint(ctx, service)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cloud endpoint: %w", err)
+		}
+		if endpointName == "" {
+			return nil, fmt.Errorf("CreateCloudEndpoint returned an empty endpoint name")
+		}
+	}
+
+	endpoint, err := c.ensurePrivateEndpoint(ctx, projectID, clusterID, endpointName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &PrivateEndpointConnection{Service: service, Endpoint: endpoint}
+	if service.DNSName != nil {
+		conn.DNSName = *service.DNSName
+	}
+	if service.Port != nil {
+		conn.Port = *service.Port
+	}
+	return conn, nil
+}
+
+// ensurePrivateEndpointService creates the private endpoint service of a
+// cluster unless it exists, and waits until it is ACTIVE.
+func (c *Client) ensurePrivateEndpointService(ctx context.Context, projectID, clusterID string, opts *WaitOptions) (*models.OpenapiPrivateEndpointService, error) {
+	var service *models.OpenapiPrivateEndpointService
+	resp, err := c.GetPrivateEndpointService(ctx, projectID, clusterID)
+	if err != nil {
+		if !stderrors.Is(err, errors.ErrNotFound) {
+			return nil, fmt.Errorf("failed to get private endpoint service: %w", err)
+		}
+	} else {
+		service = resp.PrivateEndpointService
+	}
+	if service == nil || service.Status == nil {
+		resp, err = c.CreatePrivateEndpointService(ctx, projectID, clusterID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create private endpoint service: %w", err)
+		}
+		service = resp.PrivateEndpointService
+	}
+
+	first := true
+	err = wait(ctx, "private endpoint service", opts, func(ctx context.Context) (string, bool, error) {
+		// The service fetched or created above is the first poll
+		if !first {
+			resp, err := c.GetPrivateEndpointService(ctx, projectID, clusterID)
+			if err != nil {
+				return "", false, err
+			}
+			service = resp.PrivateEndpointService
+		}
+		first = false
+
+		var status string
+		if service != nil && service.Status != nil {
+			status = *service.Status
+		}
+		switch status {
+		case models.OpenapiPrivateEndpointServiceStatusACTIVE:
+			return status, true, nil
+		case models.OpenapiPrivateEndpointServiceStatusDELETING:
+			return status, false, &PrivateEndpointFailedError{ProjectID: projectID, ClusterID: clusterID, Status: status}
+		}
+		return status, false, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed waiting for private endpoint service of cluster %s: %w", clusterID, err)
+	}
+
+	return service, nil
+}
+
+// ensurePrivateEndpoint registers the named endpoint unless ListPrivateEndpoints
+// reports it, and waits until it is ACTIVE.
+func (c *Client) ensurePrivateEndpoint(ctx context.Context, projectID, clusterID, endpointName string, opts *WaitOptions) (*models.OpenapiPrivateEndpointItem, error) {
+	endpoint, err := c.findPrivateEndpoint(ctx, projectID, clusterID, endpointName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list private endpoints: %w", err)
+	}
+	if endpoint == nil {
+		if _, err := c.CreatePrivateEndpoint(ctx, projectID, clusterID, &models.OpenapiCreatePrivateEndpointReq{EndpointName: &endpointName}); err != nil {
+			return nil, fmt.Errorf("failed to create private endpoint: %w", err)
+		}
+	}
+
+	err = wait(ctx, "private endpoint", opts, func(ctx context.Context) (string, bool, error) {
+		var err error
+		endpoint, err = c.findPrivateEndpoint(ctx, projectID, clusterID, endpointName)
+		if err != nil {
+			return "", false, err
+		}
+		// A newly created endpoint may not be listed yet
+		if endpoint == nil {
+			return "", false, nil
+		}
+
+		var status string
+		if endpoint.Status != nil {
+			status = *endpoint.Status
+		}
+		switch status {
+		case models.OpenapiPrivateEndpointStatusACTIVE:
+			return status, true, nil
+		case models.OpenapiPrivateEndpointStatusFAILED, models.OpenapiPrivateEndpointStatusDELETING:
+			failed := &PrivateEndpointFailedError{ProjectID: projectID, ClusterID: clusterID, EndpointName: endpointName, Status: status}
+			if endpoint.Message != nil {
+				failed.Message = *endpoint.Message
+			}
+			return status, false, failed
+		}
+		return status, false, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed waiting for private endpoint %s: %w", endpointName, err)
+	}
+
+	return endpoint, nil
+}
+
+// findPrivateEndpoint returns the private endpoint of a cluster with the given
+// name, or nil if there is none.
+func (c *Client) findPrivateEndpoint(ctx context.Context, projectID, clusterID, endpointName string) (*models.OpenapiPrivateEndpointItem, error) {
+	endpoints, err := c.ListPrivateEndpoints(ctx, projectID, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	for _, endpoint := range endpoints.Endpoints {
+		if endpoint.EndpointName != nil && *endpoint.EndpointName == endpointName {
+			return endpoint, nil
+		}
+	}
+	return nil, nil
+}
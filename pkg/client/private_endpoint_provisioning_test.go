@@ -0,0 +1,205 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/5st7/tidb-cloud-go/pkg/auth"
+	"github.com/5st7/tidb-cloud-go/pkg/models"
+)
+
+// privateEndpointServer fakes the private endpoint API of cluster456 with
+// responses shaped as in the API spec. The service and endpoint "vpce-123"
+// exist once created, and report their statuses in turn on every read.
+type privateEndpointServer struct {
+	serviceExists    bool
+	serviceStatuses  []string
+	endpointExists   bool
+	endpointStatuses []string
+	endpointMessage  string
+
+	serviceReads, serviceCreates   int
+	endpointReads, endpointCreates int
+}
+
+func (s *privateEndpointServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const base = "/api/v1beta/projects/project123/clusters/cluster456"
+	switch {
+	case r.URL.Path == base+"/private_endpoint_service" && r.Method == "POST":
+		s.serviceCreates++
+		s.serviceExists = true
+		fallthrough
+	case r.URL.Path == base+"/private_endpoint_service" && r.Method == "GET":
+		if !s.serviceExists {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": 49900002, "message": "private endpoint service not found"})
+			return
+		}
+		status := s.serviceStatuses[min(s.serviceReads, len(s.serviceStatuses)-1)]
+		s.serviceReads++
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{
+			"private_endpoint_service": {
+				"cloud_provider": "AWS",
+				"name": "com.amazonaws.vpce.us-west-2.vpce-svc-123",
+				"status": %q,
+				"dns_name": "privatelink-tidb.123.clusters.tidb-cloud.com",
+				"port": 4000,
+				"az_ids": ["usw2-az1", "usw2-az2"]
+			}
+		}`, status)
+	case r.URL.Path == base+"/private_endpoints" && r.Method == "POST":
+		var req models.OpenapiCreatePrivateEndpointReq
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.EndpointName == nil || *req.EndpointName != "vpce-123" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.endpointCreates++
+		s.endpointExists = true
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"private_endpoint": {"id": "ep1", "cluster_id": "cluster456", "endpoint_name": "vpce-123", "status": "PENDING"}}`)
+	case r.URL.Path == base+"/private_endpoints" && r.Method == "GET":
+		endpoints := []string{}
+		if s.endpointExists {
+			status := s.endpointStatuses[min(s.endpointReads, len(s.endpointStatuses)-1)]
+			s.endpointReads++
+			endpoints = append(endpoints, fmt.Sprintf(`{
+				"id": "ep1",
+				"cloud_provider": "AWS",
+				"cluster_id": "cluster456",
+				"region_name": "Oregon",
+				"endpoint_name": "vpce-123",
+				"status": %q,
+				"message": %q,
+				"service_name": "com.amazonaws.vpce.us-west-2.vpce-svc-123",
+				"service_status": "ACTIVE"
+			}`, status, s.endpointMessage))
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"endpoints": [%s]}`, strings.Join(endpoints, ","))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestClient_EnsurePrivateEndpoint(t *testing.T) {
+	tests := []struct {
+		name                    string
+		server                  *privateEndpointServer
+		endpointName            string
+		expectedFailed          bool
+		expectedServiceCreates  int
+		expectedEndpointCreates int
+		expectedCloudCreates    int
+	}{
+		{
+			name: "provisions service and endpoint",
+			server: &privateEndpointServer{
+				serviceStatuses:  []string{models.OpenapiPrivateEndpointServiceStatusCREATING, models.OpenapiPrivateEndpointServiceStatusCREATING, models.OpenapiPrivateEndpointServiceStatusACTIVE},
+				endpointStatuses: []string{models.OpenapiPrivateEndpointStatusPENDING, models.OpenapiPrivateEndpointStatusACTIVE},
+			},
+			expectedServiceCreates:  1,
+			expectedEndpointCreates: 1,
+			expectedCloudCreates:    1,
+		},
+		{
+			name: "reuses existing service and endpoint",
+			server: &privateEndpointServer{
+				serviceExists:    true,
+				serviceStatuses:  []string{models.OpenapiPrivateEndpointServiceStatusACTIVE},
+				endpointExists:   true,
+				endpointStatuses: []string{models.OpenapiPrivateEndpointStatusACTIVE},
+			},
+			endpointName: "vpce-123",
+		},
+		{
+			name: "endpoint fails",
+			server: &privateEndpointServer{
+				serviceExists:    true,
+				serviceStatuses:  []string{models.OpenapiPrivateEndpointServiceStatusACTIVE},
+				endpointExists:   true,
+				endpointStatuses: []string{models.OpenapiPrivateEndpointStatusPENDING, models.OpenapiPrivateEndpointStatusFAILED},
+				endpointMessage:  "The endpoint does not exist.",
+			},
+			endpointName:   "vpce-123",
+			expectedFailed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.server)
+			defer server.Close()
+
+			client, err := NewClient("", "", WithBaseURL(server.URL), WithAuthenticator(mustBearer(t, auth.StaticToken("abc123"))))
+			if err != nil {
+				t.Fatalf("NewClient() unexpected error: %v", err)
+			}
+
+			var progress []WaitProgress
+			var cloudCreates int
+			spec := &PrivateEndpointSpec{
+				EndpointName: tt.endpointName,
+				CreateCloudEndpoint: func(ctx context.Context, service *models.OpenapiPrivateEndpointService) (string, error) {
+					cloudCreates++
+					if *service.Status != models.OpenapiPrivateEndpointServiceStatusACTIVE {
+						t.Errorf("Expected ACTIVE service, got %s", *service.Status)
+					}
+					return "vpce-123", nil
+				},
+				Wait: fastWait(&progress),
+			}
+
+			conn, err := client.EnsurePrivateEndpoint(context.Background(), "project123", "cluster456", spec)
+
+			if tt.expectedFailed {
+				var failed *PrivateEndpointFailedError
+				if !stderrors.As(err, &failed) {
+					t.Fatalf("Expected PrivateEndpointFailedError, got %v", err)
+				}
+				if failed.Status != models.OpenapiPrivateEndpointStatusFAILED || failed.Message != tt.server.endpointMessage {
+					t.Errorf("Expected FAILED with message, got %+v", failed)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if conn.DNSName != "privatelink-tidb.123.clusters.tidb-cloud.com" || conn.Port != 4000 {
+				t.Errorf("Expected DNS name and port 4000, got %s:%d", conn.DNSName, conn.Port)
+			}
+			if *conn.Endpoint.Status != models.OpenapiPrivateEndpointStatusACTIVE {
+				t.Errorf("Expected ACTIVE endpoint, got %s", *conn.Endpoint.Status)
+			}
+			if tt.server.serviceCreates != tt.expectedServiceCreates {
+				t.Errorf("Expected %d service creates, got %d", tt.expectedServiceCreates, tt.server.serviceCreates)
+			}
+			if tt.server.endpointCreates != tt.expectedEndpointCreates {
+				t.Errorf("Expected %d endpoint creates, got %d", tt.expectedEndpointCreates, tt.server.endpointCreates)
+			}
+			if cloudCreates != tt.expectedCloudCreates {
+				t.Errorf("Expected %d cloud endpoint creates, got %d", tt.expectedCloudCreates, cloudCreates)
+			}
+			last := progress[len(progress)-1]
+			if last.Resource != "private endpoint" || last.Status != models.OpenapiPrivateEndpointStatusACTIVE {
+				t.Errorf("Expected last progress to be the ACTIVE endpoint, got %+v", last)
+			}
+		})
+	}
+
+	client, err := NewClient("", "", WithAuthenticator(mustBearer(t, auth.StaticToken("abc123"))))
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+	if _, err := client.EnsurePrivateEndpoint(context.Background(), "project123", "cluster456", &PrivateEndpointSpec{}); err == nil {
+		t.Error("Expected error for spec without endpoint name or CreateCloudEndpoint")
+	}
+}
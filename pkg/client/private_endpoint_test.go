@@ -31,25 +31,27 @@ func TestClient_GetPrivateEndpointService(t *testing.T) {
 					t.Errorf("Expected path /api/v1beta/projects/test-project/clusters/test-cluster/private_endpoint_service, got %s", r.URL.Path)
 				}
 
-				resp := &models.OpenapiGetPrivateEndpointServiceResp{
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{
+					"private_endpoint_service": {
+						"cloud_provider": "AWS",
+						"name": "com.amazonaws.vpce.us-east-1.vpce-svc-12345",
+						"status": "ACTIVE",
+						"dns_name": "vpce-svc-12345.us-east-1.vpce.amazonaws.com",
+						"port": 4000,
+						"az_ids": ["use1-az1", "use1-az2"]
+					}
+				}`))
+			},
+			expectedResp: &models.OpenapiGetPrivateEndpointServiceResp{
+				PrivateEndpointService: &models.OpenapiPrivateEndpointService{
 					CloudProvider: stringPtr("AWS"),
 					Name:          stringPtr("com.amazonaws.vpce.us-east-1.vpce-svc-12345"),
 					Status:        stringPtr("ACTIVE"),
 					DNSName:       stringPtr("vpce-svc-12345.us-east-1.vpce.amazonaws.com"),
 					Port:          int64Ptr(4000),
 					AzIDs:         []string{"use1-az1", "use1-az2"},
-				}
-
-				w.WriteHeader(http.StatusOK)
-				json.NewEncoder(w).Encode(resp)
-			},
-			expectedResp: &models.OpenapiGetPrivateEndpointServiceResp{
-				CloudProvider: stringPtr("AWS"),
-				Name:          stringPtr("com.amazonaws.vpce.us-east-1.vpce-svc-12345"),
-				Status:        stringPtr("ACTIVE"),
-				DNSName:       stringPtr("vpce-svc-12345.us-east-1.vpce.amazonaws.com"),
-				Port:          int64Ptr(4000),
-				AzIDs:         []string{"use1-az1", "use1-az2"},
+				},
 			},
 		},
 		{
@@ -150,25 +152,27 @@ func TestClient_CreatePrivateEndpointService(t *testing.T) {
 					t.Errorf("Failed to decode request body: %v", err)
 				}
 
-				resp := &models.OpenapiGetPrivateEndpointServiceResp{
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{
+					"private_endpoint_service": {
+						"cloud_provider": "AWS",
+						"name": "com.amazonaws.vpce.us-east-1.vpce-svc-12345",
+						"status": "CREATING",
+						"dns_name": "vpce-svc-12345.us-east-1.vpce.amazonaws.com",
+						"port": 4000,
+						"az_ids": ["use1-az1", "use1-az2"]
+					}
+				}`))
+			},
+			expectedResp: &models.OpenapiGetPrivateEndpointServiceResp{
+				PrivateEndpointService: &models.OpenapiPrivateEndpointService{
 					CloudProvider: stringPtr("AWS"),
 					Name:          stringPtr("com.amazonaws.vpce.us-east-1.vpce-svc-12345"),
 					Status:        stringPtr("CREATING"),
 					DNSName:       stringPtr("vpce-svc-12345.us-east-1.vpce.amazonaws.com"),
 					Port:          int64Ptr(4000),
 					AzIDs:         []string{"use1-az1", "use1-az2"},
-				}
-
-				w.WriteHeader(http.StatusOK)
-				json.NewEncoder(w).Encode(resp)
-			},
-			expectedResp: &models.OpenapiGetPrivateEndpointServiceResp{
-				CloudProvider: stringPtr("AWS"),
-				Name:          stringPtr("com.amazonaws.vpce.us-east-1.vpce-svc-12345"),
-				Status:        stringPtr("CREATING"),
-				DNSName:       stringPtr("vpce-svc-12345.us-east-1.vpce.amazonaws.com"),
-				Port:          int64Ptr(4000),
-				AzIDs:         []string{"use1-az1", "use1-az2"},
+				},
 			},
 		},
 		{
@@ -251,33 +255,30 @@ func TestClient_ListPrivateEndpoints(t *testing.T) {
 					t.Errorf("Expected path /api/v1beta/projects/test-project/clusters/test-cluster/private_endpoints, got %s", r.URL.Path)
 				}
 
-				resp := &models.OpenapiListPrivateEndpointsResp{
-					Items: []*models.OpenapiPrivateEndpointItem{
-						{
-							ID:            stringPtr("pe-123"),
-							CloudProvider: stringPtr("AWS"),
-							ClusterID:     stringPtr("test-cluster"),
-							Region:        stringPtr("us-east-1"),
-							EndpointName:  stringPtr("vpce-12345"),
-							Status:        stringPtr("ACTIVE"),
-							Message:       stringPtr(""),
-							ServiceName:   stringPtr("com.amazonaws.vpce.us-east-1.vpce-svc-12345"),
-							ServiceStatus: stringPtr("ACTIVE"),
-						},
-					},
-					Total: int64Ptr(1),
-				}
-
 				w.WriteHeader(http.StatusOK)
-				json.NewEncoder(w).Encode(resp)
+				w.Write([]byte(`{
+					"endpoints": [{
+						"id": "pe-123",
+						"cloud_provider": "AWS",
+						"cluster_id": "test-cluster",
+						"cluster_name": "Cluster0",
+						"region_name": "Virginia",
+						"endpoint_name": "vpce-12345",
+						"status": "ACTIVE",
+						"message": "",
+						"service_name": "com.amazonaws.vpce.us-east-1.vpce-svc-12345",
+						"service_status": "ACTIVE"
+					}]
+				}`))
 			},
 			expectedResp: &models.OpenapiListPrivateEndpointsResp{
-				Items: []*models.OpenapiPrivateEndpointItem{
+				Endpoints: []*models.OpenapiPrivateEndpointItem{
 					{
 						ID:            stringPtr("pe-123"),
 						CloudProvider: stringPtr("AWS"),
 						ClusterID:     stringPtr("test-cluster"),
-						Region:        stringPtr("us-east-1"),
+						ClusterName:   stringPtr("Cluster0"),
+						RegionName:    stringPtr("Virginia"),
 						EndpointName:  stringPtr("vpce-12345"),
 						Status:        stringPtr("ACTIVE"),
 						Message:       stringPtr(""),
@@ -285,7 +286,6 @@ func TestClient_ListPrivateEndpoints(t *testing.T) {
 						ServiceStatus: stringPtr("ACTIVE"),
 					},
 				},
-				Total: int64Ptr(1),
 			},
 		},
 		{
@@ -381,31 +381,33 @@ func TestClient_CreatePrivateEndpoint(t *testing.T) {
 					t.Errorf("Expected endpoint name 'vpce-12345', got %v", body.EndpointName)
 				}
 
-				resp := &models.OpenapiCreatePrivateEndpointResp{
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{
+					"private_endpoint": {
+						"id": "pe-123",
+						"cloud_provider": "AWS",
+						"cluster_id": "test-cluster",
+						"region_name": "Virginia",
+						"endpoint_name": "vpce-12345",
+						"status": "PENDING",
+						"message": "Creating private endpoint",
+						"service_name": "com.amazonaws.vpce.us-east-1.vpce-svc-12345",
+						"service_status": "ACTIVE"
+					}
+				}`))
+			},
+			expectedResp: &models.OpenapiCreatePrivateEndpointResp{
+				PrivateEndpoint: &models.OpenapiPrivateEndpointItem{
 					ID:            stringPtr("pe-123"),
 					CloudProvider: stringPtr("AWS"),
 					ClusterID:     stringPtr("test-cluster"),
-					Region:        stringPtr("us-east-1"),
+					RegionName:    stringPtr("Virginia"),
 					EndpointName:  stringPtr("vpce-12345"),
 					Status:        stringPtr("PENDING"),
 					Message:       stringPtr("Creating private endpoint"),
 					ServiceName:   stringPtr("com.amazonaws.vpce.us-east-1.vpce-svc-12345"),
 					ServiceStatus: stringPtr("ACTIVE"),
-				}
-
-				w.WriteHeader(http.StatusOK)
-				json.NewEncoder(w).Encode(resp)
-			},
-			expectedResp: &models.OpenapiCreatePrivateEndpointResp{
-				ID:            stringPtr("pe-123"),
-				CloudProvider: stringPtr("AWS"),
-				ClusterID:     stringPtr("test-cluster"),
-				Region:        stringPtr("us-east-1"),
-				EndpointName:  stringPtr("vpce-12345"),
-				Status:        stringPtr("PENDING"),
-				Message:       stringPtr("Creating private endpoint"),
-				ServiceName:   stringPtr("com.amazonaws.vpce.us-east-1.vpce-svc-12345"),
-				ServiceStatus: stringPtr("ACTIVE"),
+				},
 			},
 		},
 		{
@@ -589,44 +591,38 @@ func TestClient_ListPrivateEndpointsOfProject(t *testing.T) {
 					t.Errorf("Expected path /api/v1beta/projects/test-project/private_endpoints, got %s", r.URL.Path)
 				}
 
-				resp := &models.OpenapiListPrivateEndpointsResp{
-					Items: []*models.OpenapiPrivateEndpointItem{
-						{
-							ID:            stringPtr("pe-123"),
-							CloudProvider: stringPtr("AWS"),
-							ClusterID:     stringPtr("cluster-1"),
-							Region:        stringPtr("us-east-1"),
-							EndpointName:  stringPtr("vpce-12345"),
-							Status:        stringPtr("ACTIVE"),
-							Message:       stringPtr(""),
-							ServiceName:   stringPtr("com.amazonaws.vpce.us-east-1.vpce-svc-12345"),
-							ServiceStatus: stringPtr("ACTIVE"),
-						},
-						{
-							ID:            stringPtr("pe-456"),
-							CloudProvider: stringPtr("AWS"),
-							ClusterID:     stringPtr("cluster-2"),
-							Region:        stringPtr("us-west-2"),
-							EndpointName:  stringPtr("vpce-67890"),
-							Status:        stringPtr("ACTIVE"),
-							Message:       stringPtr(""),
-							ServiceName:   stringPtr("com.amazonaws.vpce.us-west-2.vpce-svc-67890"),
-							ServiceStatus: stringPtr("ACTIVE"),
-						},
-					},
-					Total: int64Ptr(2),
-				}
-
 				w.WriteHeader(http.StatusOK)
-				json.NewEncoder(w).Encode(resp)
+				w.Write([]byte(`{
+					"endpoints": [{
+						"id": "pe-123",
+						"cloud_provider": "AWS",
+						"cluster_id": "cluster-1",
+						"region_name": "Virginia",
+						"endpoint_name": "vpce-12345",
+						"status": "ACTIVE",
+						"message": "",
+						"service_name": "com.amazonaws.vpce.us-east-1.vpce-svc-12345",
+						"service_status": "ACTIVE"
+					}, {
+						"id": "pe-456",
+						"cloud_provider": "AWS",
+						"cluster_id": "cluster-2",
+						"region_name": "Oregon",
+						"endpoint_name": "vpce-67890",
+						"status": "ACTIVE",
+						"message": "",
+						"service_name": "com.amazonaws.vpce.us-west-2.vpce-svc-67890",
+						"service_status": "ACTIVE"
+					}]
+				}`))
 			},
 			expectedResp: &models.OpenapiListPrivateEndpointsResp{
-				Items: []*models.OpenapiPrivateEndpointItem{
+				Endpoints: []*models.OpenapiPrivateEndpointItem{
 					{
 						ID:            stringPtr("pe-123"),
 						CloudProvider: stringPtr("AWS"),
 						ClusterID:     stringPtr("cluster-1"),
-						Region:        stringPtr("us-east-1"),
+						RegionName:    stringPtr("Virginia"),
 						EndpointName:  stringPtr("vpce-12345"),
 						Status:        stringPtr("ACTIVE"),
 						Message:       stringPtr(""),
@@ -637,7 +633,7 @@ func TestClient_ListPrivateEndpointsOfProject(t *testing.T) {
 						ID:            stringPtr("pe-456"),
 						CloudProvider: stringPtr("AWS"),
 						ClusterID:     stringPtr("cluster-2"),
-						Region:        stringPtr("us-west-2"),
+						RegionName:    stringPtr("Oregon"),
 						EndpointName:  stringPtr("vpce-67890"),
 						Status:        stringPtr("ACTIVE"),
 						Message:       stringPtr(""),
@@ -645,7 +641,6 @@ func TestClient_ListPrivateEndpointsOfProject(t *testing.T) {
 						ServiceStatus: stringPtr("ACTIVE"),
 					},
 				},
-				Total: int64Ptr(2),
 			},
 		},
 		{
@@ -706,6 +701,17 @@ func privateEndpointServiceEqual(a, b *models.OpenapiGetPrivateEndpointServiceRe
 		return false
 	}
 
+	return privateEndpointServiceDetailsEqual(a.PrivateEndpointService, b.PrivateEndpointService)
+}
+
+func privateEndpointServiceDetailsEqual(a, b *models.OpenapiPrivateEndpointService) bool {
+	if a == nil && b == nil {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+
 	return stringPtrEqual(a.CloudProvider, b.CloudProvider) &&
 		stringPtrEqual(a.Name, b.Name) &&
 		stringPtrEqual(a.Status, b.Status) &&
@@ -722,16 +728,12 @@ func privateEndpointsEqual(a, b *models.OpenapiListPrivateEndpointsResp) bool {
 		return false
 	}
 
-	if !int64PtrEqual(a.Total, b.Total) {
+	if len(a.Endpoints) != len(b.Endpoints) {
 		return false
 	}
 
-	if len(a.Items) != len(b.Items) {
-		return false
-	}
-
-	for i, item := range a.Items {
-		if !privateEndpointItemEqual(item, b.Items[i]) {
+	for i, item := range a.Endpoints {
+		if !privateEndpointItemEqual(item, b.Endpoints[i]) {
 			return false
 		}
 	}
@@ -750,7 +752,8 @@ func privateEndpointItemEqual(a, b *models.OpenapiPrivateEndpointItem) bool {
 	return stringPtrEqual(a.ID, b.ID) &&
 		stringPtrEqual(a.CloudProvider, b.CloudProvider) &&
 		stringPtrEqual(a.ClusterID, b.ClusterID) &&
-		stringPtrEqual(a.Region, b.Region) &&
+		stringPtrEqual(a.ClusterName, b.ClusterName) &&
+		stringPtrEqual(a.RegionName, b.RegionName) &&
 		stringPtrEqual(a.EndpointName, b.EndpointName) &&
 		stringPtrEqual(a.Status, b.Status) &&
 		stringPtrEqual(a.Message, b.Message) &&
@@ -766,15 +769,7 @@ func createPrivateEndpointEqual(a, b *models.OpenapiCreatePrivateEndpointResp) b
 		return false
 	}
 
-	return stringPtrEqual(a.ID, b.ID) &&
-		stringPtrEqual(a.CloudProvider, b.CloudProvider) &&
-		stringPtrEqual(a.ClusterID, b.ClusterID) &&
-		stringPtrEqual(a.Region, b.Region) &&
-		stringPtrEqual(a.EndpointName, b.EndpointName) &&
-		stringPtrEqual(a.Status, b.Status) &&
-		stringPtrEqual(a.Message, b.Message) &&
-		stringPtrEqual(a.ServiceName, b.ServiceName) &&
-		stringPtrEqual(a.ServiceStatus, b.ServiceStatus)
+	return privateEndpointItemEqual(a.PrivateEndpoint, b.PrivateEndpoint)
 }
 
 func stringSliceEqual(a, b []string) bool {
@@ -1,23 +1,30 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/5st7/tidb-cloud-go/pkg/models"
 )
 
 // ListProviderRegions lists all available cloud providers, regions and specifications
 func (c *Client) ListProviderRegions() (*models.OpenapiListProviderRegionsResp, error) {
-	url := fmt.Sprintf("%s/api/%s/clusters/provider/regions", c.baseURL, APIVersion)
+	return c.ListProviderRegionsWithContext(context.Background())
+}
+
+// ListProviderRegionsWithContext lists all available cloud providers, regions and specifications using ctx for cancellation and deadlines
+func (c *Client) ListProviderRegionsWithContext(ctx context.Context) (*models.OpenapiListProviderRegionsResp, error) {
+	url := fmt.Sprintf("%s/api/%s/clusters/provider/regions", c.baseURL, c.apiVersion)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.doRequest(req)
+	resp, err := c.doRequestWithRetry(ctx, OpListProviderRegions, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -34,3 +41,198 @@ func (c *Client) ListProviderRegions() (*models.OpenapiListProviderRegionsResp,
 
 	return &regions, nil
 }
+
+// Component identifies a TiDB cluster component in provider region profiles.
+type Component string
+
+const (
+	// ComponentTiDB is the TiDB SQL layer
+	ComponentTiDB Component = "tidb"
+	// ComponentTiKV is the TiKV row storage layer
+	ComponentTiKV Component = "tikv"
+	// ComponentTiFlash is the TiFlash columnar storage layer
+	ComponentTiFlash Component = "tiflash"
+)
+
+// NodeProfile describes a node size offered for a component in a region,
+// flattened from the TiDB, TiKV and TiFlash profiles of ListProviderRegions.
+// A zero value in a range field means the API did not report that bound.
+type NodeProfile struct {
+	Component         Component
+	NodeSize          string
+	MinNodeQuantity   int64
+	NodeQuantityStep  int64
+	MinStorageSizeGib int64
+	MaxStorageSizeGib int64
+}
+
+// HasStorage reports whether the component of the profile takes a storage size,
+// which is the case for TiKV and TiFlash.
+func (p NodeProfile) HasStorage() bool {
+	return p.Component == ComponentTiKV || p.Component == ComponentTiFlash
+}
+
+// ProviderRegions answers questions about the cloud providers, regions and
+// node profiles returned by ListProviderRegions.
+// Cloud provider and cluster type are matched case-insensitively; an empty
+// cluster type matches items of any type.
+type ProviderRegions struct {
+	items []*models.OpenapiListProviderRegionsItem
+}
+
+// NewProviderRegions wraps a ListProviderRegions response for querying.
+func NewProviderRegions(resp *models.OpenapiListProviderRegionsResp) *ProviderRegions {
+	if resp == nil {
+		return &ProviderRegions{}
+	}
+	return &ProviderRegions{items: resp.Items}
+}
+
+// LoadProviderRegions calls ListProviderRegions and wraps the result for querying.
+func (c *Client) LoadProviderRegions() (*ProviderRegions, error) {
+	return c.LoadProviderRegionsWithContext(context.Background())
+}
+
+// LoadProviderRegionsWithContext calls ListProviderRegionsWithContext and wraps the result for querying.
+func (c *Client) LoadProviderRegionsWithContext(ctx context.Context) (*ProviderRegions, error) {
+	resp, err := c.ListProviderRegionsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewProviderRegions(resp), nil
+}
+
+// Find returns the item for the given cloud provider, region and cluster type,
+// or nil if the combination is not offered.
+func (p *ProviderRegions) Find(cloudProvider, region, clusterType string) *models.OpenapiListProviderRegionsItem {
+	for _, item := range p.items {
+		if item == nil {
+			continue
+		}
+		if !strings.EqualFold(stringValue(item.CloudProvider), cloudProvider) {
+			continue
+		}
+		if stringValue(item.Region) != region {
+			continue
+		}
+		if clusterType != "" && !strings.EqualFold(stringValue(item.ClusterType), clusterType) {
+			continue
+		}
+		return item
+	}
+	return nil
+}
+
+// Regions returns the regions offered by a cloud provider for a cluster type.
+func (p *ProviderRegions) Regions(cloudProvider, clusterType string) []string {
+	var regions []string
+	seen := make(map[string]bool)
+	for _, item := range p.items {
+		if item == nil || item.Region == nil || seen[*item.Region] {
+			continue
+		}
+		if !strings.EqualFold(stringValue(item.CloudProvider), cloudProvider) {
+			continue
+		}
+		if clusterType != "" && !strings.EqualFold(stringValue(item.ClusterType), clusterType) {
+			continue
+		}
+		seen[*item.Region] = true
+		regions = append(regions, *item.Region)
+	}
+	return regions
+}
+
+// Profiles returns the node profiles of a component for the given cloud provider,
+// region and cluster type.
+func (p *ProviderRegions) Profiles(cloudProvider, region, clusterType string, component Component) []NodeProfile {
+	item := p.Find(cloudProvider, region, clusterType)
+	if item == nil {
+		return nil
+	}
+
+	var profiles []NodeProfile
+	switch component {
+	case ComponentTiDB:
+		for _, profile := range item.TiDB {
+			if profile == nil {
+				continue
+			}
+			np := NodeProfile{Component: component, NodeSize: stringValue(profile.NodeSize)}
+			applyQuantityRange(&np, profile.NodeQuantityRange)
+			profiles = append(profiles, np)
+		}
+	case ComponentTiKV:
+		for _, profile := range item.TiKV {
+			if profile == nil {
+				continue
+			}
+			np := NodeProfile{Component: component, NodeSize: stringValue(profile.NodeSize)}
+			applyQuantityRange(&np, profile.NodeQuantityRange)
+			applyStorageRange(&np, profile.StorageSizeGibRange)
+			profiles = append(profiles, np)
+		}
+	case ComponentTiFlash:
+		for _, profile := range item.TiFlash {
+			if profile == nil {
+				continue
+			}
+			np := NodeProfile{Component: component, NodeSize: stringValue(profile.NodeSize)}
+			applyQuantityRange(&np, profile.NodeQuantityRange)
+			applyStorageRange(&np, profile.StorageSizeGibRange)
+			profiles = append(profiles, np)
+		}
+	}
+	return profiles
+}
+
+// Profile returns the node profile of a component with the given node size.
+// The second return value is false if the node size is not offered.
+func (p *ProviderRegions) Profile(cloudProvider, region, clusterType string, component Component, nodeSize string) (NodeProfile, bool) {
+	for _, profile := range p.Profiles(cloudProvider, region, clusterType, component) {
+		if profile.NodeSize == nodeSize {
+			return profile, true
+		}
+	}
+	return NodeProfile{}, false
+}
+
+// NodeSizes returns the node sizes offered for a component, for example the
+// valid TiKV node sizes of DEDICATED clusters in AWS us-west-2.
+func (p *ProviderRegions) NodeSizes(cloudProvider, region, clusterType string, component Component) []string {
+	var sizes []string
+	for _, profile := range p.Profiles(cloudProvider, region, clusterType, component) {
+		sizes = append(sizes, profile.NodeSize)
+	}
+	return sizes
+}
+
+func applyQuantityRange(np *NodeProfile, r *models.OpenapiNodeQuantityRange) {
+	if r == nil {
+		return
+	}
+	np.MinNodeQuantity = int64Value(r.Min)
+	np.NodeQuantityStep = int64Value(r.Step)
+}
+
+func applyStorageRange(np *NodeProfile, r *models.OpenapiNodeStorageSizeRange) {
+	if r == nil {
+		return
+	}
+	np.MinStorageSizeGib = int64Value(r.Min)
+	np.MaxStorageSizeGib = int64Value(r.Max)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func int64Value(i *int64) int64 {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
@@ -123,3 +123,79 @@ func TestClient_ListProviderRegions(t *testing.T) {
 func boolPtr(b bool) *bool {
 	return &b
 }
+
+func testProviderRegions() *ProviderRegions {
+	return NewProviderRegions(&models.OpenapiListProviderRegionsResp{
+		Items: []*models.OpenapiListProviderRegionsItem{
+			{
+				ClusterType:   stringPtr("DEDICATED"),
+				CloudProvider: stringPtr("AWS"),
+				Region:        stringPtr("us-west-2"),
+				TiDB: []*models.OpenapiTiDBProfile{
+					{NodeSize: stringPtr("8C16G"), NodeQuantityRange: &models.OpenapiNodeQuantityRange{Min: int64Ptr(1), Step: int64Ptr(1)}},
+				},
+				TiKV: []*models.OpenapiTiKVProfile{
+					{
+						NodeSize:            stringPtr("8C32G"),
+						NodeQuantityRange:   &models.OpenapiNodeQuantityRange{Min: int64Ptr(3), Step: int64Ptr(3)},
+						StorageSizeGibRange: &models.OpenapiNodeStorageSizeRange{Min: int64Ptr(500), Max: int64Ptr(4096)},
+					},
+					{
+						NodeSize:            stringPtr("16C64G"),
+						NodeQuantityRange:   &models.OpenapiNodeQuantityRange{Min: int64Ptr(3), Step: int64Ptr(3)},
+						StorageSizeGibRange: &models.OpenapiNodeStorageSizeRange{Min: int64Ptr(500), Max: int64Ptr(6144)},
+					},
+				},
+			},
+			{
+				ClusterType:   stringPtr("DEVELOPER"),
+				CloudProvider: stringPtr("AWS"),
+				Region:        stringPtr("us-west-2"),
+			},
+			{
+				ClusterType:   stringPtr("DEDICATED"),
+				CloudProvider: stringPtr("GCP"),
+				Region:        stringPtr("us-central1"),
+			},
+		},
+	})
+}
+
+func TestProviderRegions_Query(t *testing.T) {
+	regions := testProviderRegions()
+
+	if item := regions.Find("aws", "us-west-2", "dedicated"); item == nil || *item.ClusterType != "DEDICATED" {
+		t.Errorf("Expected DEDICATED item for aws/us-west-2, got %+v", item)
+	}
+	if item := regions.Find("AWS", "eu-west-1", "DEDICATED"); item != nil {
+		t.Errorf("Expected no item for eu-west-1, got %+v", item)
+	}
+
+	sizes := regions.NodeSizes("AWS", "us-west-2", "DEDICATED", ComponentTiKV)
+	if !stringSliceEqual(sizes, []string{"8C32G", "16C64G"}) {
+		t.Errorf("Unexpected TiKV node sizes %v", sizes)
+	}
+	if sizes := regions.NodeSizes("AWS", "us-west-2", "DEDICATED", ComponentTiFlash); len(sizes) != 0 {
+		t.Errorf("Expected no TiFlash node sizes, got %v", sizes)
+	}
+
+	profile, ok := regions.Profile("AWS", "us-west-2", "DEDICATED", ComponentTiKV, "16C64G")
+	if !ok {
+		t.Fatal("Expected 16C64G TiKV profile")
+	}
+	if profile.MinNodeQuantity != 3 || profile.NodeQuantityStep != 3 || profile.MaxStorageSizeGib != 6144 {
+		t.Errorf("Unexpected profile %+v", profile)
+	}
+	if !profile.HasStorage() {
+		t.Error("Expected TiKV profile to have storage")
+	}
+
+	tidb, ok := regions.Profile("AWS", "us-west-2", "DEDICATED", ComponentTiDB, "8C16G")
+	if !ok || tidb.HasStorage() || tidb.MinNodeQuantity != 1 {
+		t.Errorf("Unexpected TiDB profile %+v", tidb)
+	}
+
+	if got := regions.Regions("GCP", "DEDICATED"); !stringSliceEqual(got, []string{"us-central1"}) {
+		t.Errorf("Unexpected GCP regions %v", got)
+	}
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/5st7/tidb-cloud-go/pkg/errors"
+	"github.com/5st7/tidb-cloud-go/pkg/models"
+)
+
+// reconcileClockSkew is how much earlier than the request a resource found by
+// a reconciliation lookup may have been created, to allow for clock skew
+// between the client and TiDB Cloud.
+const reconcileClockSkew = time.Minute
+
+// errAmbiguousMatch is returned by a reconciliation lookup that found more
+// than one resource the request may have created.
+var errAmbiguousMatch = stderrors.New("more than one resource matches the request")
+
+// reconcileFunc reports whether an earlier attempt of a non-idempotent request
+// took effect, e.g. by finding the resource it creates.
+type reconcileFunc func(ctx context.Context) (bool, error)
+
+// mayHaveBeenProcessed reports whether the server may have acted on a request
+// that failed with err. Rate-limited and unavailable responses, and requests
+// that could not connect, were not processed; other server errors and lost
+// responses may have been.
+func mayHaveBeenProcessed(err error) bool {
+	var apiErr errors.APIError
+	if stderrors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+			return false
+		}
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var opErr *net.OpError
+	if stderrors.As(err, &opErr) && opErr.Op == "dial" {
+		return false
+	}
+	return true
+}
+
+// createdSince reports whether a resource with the given create timestamp, in
+// Unix seconds, may have been created by a request sent at since. A missing
+// timestamp cannot rule it out.
+func createdSince(timestamp *string, since time.Time) bool {
+	if timestamp == nil {
+		return true
+	}
+	seconds, err := strconv.ParseInt(*timestamp, 10, 64)
+	if err != nil {
+		return true
+	}
+	return !time.Unix(seconds, 0).Before(since.Add(-reconcileClockSkew))
+}
+
+// findCreatedCluster returns the cluster a CreateCluster request sent at since
+// created, or nil if there is none. Cluster names are unique in a project.
+func (c *Client) findCreatedCluster(ctx context.Context, projectID string, req *models.OpenapiCreateClusterReq, since time.Time) (*models.OpenapiCreateClusterResp, error) {
+	for cluster, err := range c.AllClusters(ctx, projectID) {
+		if err != nil {
+			return nil, err
+		}
+		if cluster.Name != nil && *cluster.Name == *req.Name && createdSince(cluster.CreateTimestamp, since) {
+			return &models.OpenapiCreateClusterResp{ClusterID: cluster.ID}, nil
+		}
+	}
+	return nil, nil
+}
+
+// findCreatedBackup returns the backup a CreateBackup request sent at since
+// created, or nil if there is none. Backup names are not unique, so a backup
+// must also match the cluster and description, and more than one match is
+// reported as an error rather than guessed at.
+func (c *Client) findCreatedBackup(ctx context.Context, projectID, clusterID string, req *models.OpenapiCreateBackupReq, since time.Time) (*models.OpenapiCreateBackupResp, error) {
+	var created *models.OpenapiCreateBackupResp
+	for backup, err := range c.AllBackups(ctx, projectID, clusterID) {
+		if err != nil {
+			return nil, err
+		}
+		if backup.Name == nil || *backup.Name != *req.Name || !createdSince(backup.CreateTimestamp, since) {
+			continue
+		}
+		if backup.ClusterID != nil && *backup.ClusterID != clusterID {
+			continue
+		}
+		if stringValue(backup.Description) != stringValue(req.Description) {
+			continue
+		}
+		if created != nil {
+			return nil, errAmbiguousMatch
+		}
+		created = &models.OpenapiCreateBackupResp{BackupID: backup.ID}
+	}
+	return created, nil
+}
+
+// findCreatedRestore returns the restore a CreateRestore request sent at since
+// created, or nil if there is none.
+func (c *Client) findCreatedRestore(ctx context.Context, projectID string, req *models.OpenapiCreateRestoreReq, since time.Time) (*models.OpenapiCreateRestoreResp, error) {
+	for restore, err := range c.AllRestores(ctx, projectID) {
+		if err != nil {
+			return nil, err
+		}
+		if restore.Name == nil || *restore.Name != *req.Name || !createdSince(restore.CreateTimestamp, since) {
+			continue
+		}
+		if req.BackupID != nil && (restore.BackupID == nil || *restore.BackupID != *req.BackupID) {
+			continue
+		}
+		return &models.OpenapiCreateRestoreResp{RestoreID: restore.ID}, nil
+	}
+	return nil, nil
+}
+
+// findCreatedPrivateEndpoint returns the private endpoint a
+// CreatePrivateEndpoint request created, or nil if there is none. Endpoint
+// names, such as AWS VPC endpoint IDs, identify a single endpoint.
+func (c *Client) findCreatedPrivateEndpoint(ctx context.Context, projectID, clusterID string, req *models.OpenapiCreatePrivateEndpointReq) (*models.OpenapiCreatePrivateEndpointResp, error) {
+	endpoint, err := c.findPrivateEndpoint(ctx, projectID, clusterID, *req.EndpointName)
+	if err != nil || endpoint == nil {
+		return nil, err
+	}
+	return &models.OpenapiCreatePrivateEndpointResp{PrivateEndpoint: endpoint}, nil
+}
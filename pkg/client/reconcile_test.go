@@ -0,0 +1,339 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/5st7/tidb-cloud-go/pkg/auth"
+	"github.com/5st7/tidb-cloud-go/pkg/errors"
+	"github.com/5st7/tidb-cloud-go/pkg/models"
+	"github.com/5st7/tidb-cloud-go/pkg/retry"
+)
+
+// newReconcileTestClient creates a client for server that retries quickly.
+func newReconcileTestClient(t *testing.T, server *httptest.Server) *Client {
+	t.Helper()
+	client, err := NewClient("", "",
+		WithBaseURL(server.URL),
+		WithAuthenticator(mustBearer(t, auth.StaticToken("abc123"))),
+		WithRetryPolicy(&retry.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+	return client
+}
+
+func TestClient_CreateClusterReconcile(t *testing.T) {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	tests := []struct {
+		name          string
+		firstStatus   int
+		existing      []*models.OpenapiClusterItem
+		expectedPosts int
+		expectedLists int
+		expectedID    string
+	}{
+		{
+			name:          "first attempt created the cluster",
+			firstStatus:   http.StatusBadGateway,
+			existing:      []*models.OpenapiClusterItem{{ID: stringPtr("other"), Name: stringPtr("other-cluster")}, {ID: stringPtr("created"), Name: stringPtr("my-cluster"), CreateTimestamp: &now}},
+			expectedPosts: 1,
+			expectedLists: 1,
+			expectedID:    "created",
+		},
+		{
+			name:          "first attempt had no effect",
+			firstStatus:   http.StatusBadGateway,
+			existing:      []*models.OpenapiClusterItem{{ID: stringPtr("stale"), Name: stringPtr("my-cluster"), CreateTimestamp: &old}},
+			expectedPosts: 2,
+			expectedLists: 1,
+			expectedID:    "new",
+		},
+		{
+			name:          "rate limited request is retried without lookup",
+			firstStatus:   http.StatusTooManyRequests,
+			expectedPosts: 2,
+			expectedLists: 0,
+			expectedID:    "new",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var posts, lists int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				switch r.Method {
+				case "POST":
+					posts++
+					if posts == 1 {
+						w.WriteHeader(tt.firstStatus)
+						return
+					}
+					w.WriteHeader(http.StatusOK)
+					json.NewEncoder(w).Encode(&models.OpenapiCreateClusterResp{ClusterID: stringPtr("new")})
+				case "GET":
+					lists++
+					total := int64(len(tt.existing))
+					w.WriteHeader(http.StatusOK)
+					json.NewEncoder(w).Encode(&models.OpenapiListClustersOfProjectResp{Items: tt.existing, Total: &total})
+				}
+			}))
+			defer server.Close()
+
+			client := newReconcileTestClient(t, server)
+			resp, err := client.CreateCluster("project123", &models.OpenapiCreateClusterReq{Name: stringPtr("my-cluster")})
+			if err != nil {
+				t.Fatalf("CreateCluster() unexpected error: %v", err)
+			}
+
+			if resp.ClusterID == nil || *resp.ClusterID != tt.expectedID {
+				t.Errorf("Expected cluster ID %s, got %v", tt.expectedID, resp.ClusterID)
+			}
+			if posts != tt.expectedPosts {
+				t.Errorf("Expected %d POST requests, got %d", tt.expectedPosts, posts)
+			}
+			if lists != tt.expectedLists {
+				t.Errorf("Expected %d list requests, got %d", tt.expectedLists, lists)
+			}
+		})
+	}
+}
+
+func TestClient_CreateBackupReconcile(t *testing.T) {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	tests := []struct {
+		name          string
+		existing      []*models.OpenapiListBackupItem
+		expectedPosts int
+		expectedID    string
+		expectError   bool
+	}{
+		{
+			name: "description identifies the created backup",
+			existing: []*models.OpenapiListBackupItem{
+				{ID: stringPtr("other"), Name: stringPtr("nightly"), Description: stringPtr("before upgrade"), ClusterID: stringPtr("cluster456"), CreateTimestamp: &now},
+				{ID: stringPtr("created"), Name: stringPtr("nightly"), Description: stringPtr("before migration"), ClusterID: stringPtr("cluster456"), CreateTimestamp: &now},
+			},
+			expectedPosts: 1,
+			expectedID:    "created",
+		},
+		{
+			name: "backup of another cluster is ignored",
+			existing: []*models.OpenapiListBackupItem{
+				{ID: stringPtr("other"), Name: stringPtr("nightly"), Description: stringPtr("before migration"), ClusterID: stringPtr("cluster789"), CreateTimestamp: &now},
+			},
+			expectedPosts: 2,
+			expectedID:    "new",
+		},
+		{
+			name: "several matching backups are not reconciled",
+			existing: []*models.OpenapiListBackupItem{
+				{ID: stringPtr("first"), Name: stringPtr("nightly"), Description: stringPtr("before migration"), ClusterID: stringPtr("cluster456"), CreateTimestamp: &now},
+				{ID: stringPtr("second"), Name: stringPtr("nightly"), Description: stringPtr("before migration"), ClusterID: stringPtr("cluster456"), CreateTimestamp: &now},
+			},
+			expectedPosts: 1,
+			expectError:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var posts int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				switch r.Method {
+				case "POST":
+					posts++
+					if posts == 1 {
+						w.WriteHeader(http.StatusBadGateway)
+						return
+					}
+					w.WriteHeader(http.StatusOK)
+					json.NewEncoder(w).Encode(&models.OpenapiCreateBackupResp{BackupID: stringPtr("new")})
+				case "GET":
+					total := int64(len(tt.existing))
+					w.WriteHeader(http.StatusOK)
+					json.NewEncoder(w).Encode(&models.OpenapiListBackupOfClusterResp{Items: tt.existing, Total: &total})
+				}
+			}))
+			defer server.Close()
+
+			client := newReconcileTestClient(t, server)
+			resp, err := client.CreateBackup("project123", "cluster456", &models.OpenapiCreateBackupReq{Name: stringPtr("nightly"), Description: stringPtr("before migration")})
+			if posts != tt.expectedPosts {
+				t.Errorf("Expected %d POST requests, got %d", tt.expectedPosts, posts)
+			}
+			if tt.expectError {
+				var apiErr errors.APIError
+				if !stderrors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+					t.Errorf("Expected API error with status %d, got %v", http.StatusBadGateway, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateBackup() unexpected error: %v", err)
+			}
+			if resp.BackupID == nil || *resp.BackupID != tt.expectedID {
+				t.Errorf("Expected backup ID %s, got %v", tt.expectedID, resp.BackupID)
+			}
+		})
+	}
+}
+
+func TestClient_CreatePrivateEndpointReconcile(t *testing.T) {
+	var posts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			posts++
+			w.WriteHeader(http.StatusGatewayTimeout)
+			return
+		}
+		if r.URL.Path != "/api/v1beta/projects/project123/clusters/cluster456/private_endpoints" {
+			t.Errorf("Unexpected lookup path %s", r.URL.Path)
+		}
+		// ListPrivateEndpointsResp as documented by the API spec
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"endpoints": [{
+				"id": "pe-0",
+				"cloud_provider": "AWS",
+				"cluster_id": "cluster456",
+				"cluster_name": "Cluster0",
+				"region_name": "Oregon",
+				"endpoint_name": "vpce-000",
+				"status": "ACTIVE",
+				"service_name": "com.amazonaws.vpce.us-west-2.vpce-svc-123",
+				"service_status": "ACTIVE"
+			}, {
+				"id": "pe-1",
+				"cloud_provider": "AWS",
+				"cluster_id": "cluster456",
+				"cluster_name": "Cluster0",
+				"region_name": "Oregon",
+				"endpoint_name": "vpce-123",
+				"status": "PENDING",
+				"service_name": "com.amazonaws.vpce.us-west-2.vpce-svc-123",
+				"service_status": "ACTIVE"
+			}]
+		}`))
+	}))
+	defer server.Close()
+
+	client := newReconcileTestClient(t, server)
+	resp, err := client.CreatePrivateEndpoint(context.Background(), "project123", "cluster456", &models.OpenapiCreatePrivateEndpointReq{EndpointName: stringPtr("vpce-123")})
+	if err != nil {
+		t.Fatalf("CreatePrivateEndpoint() unexpected error: %v", err)
+	}
+	endpoint := resp.PrivateEndpoint
+	if endpoint == nil || endpoint.ID == nil || *endpoint.ID != "pe-1" || endpoint.Status == nil || *endpoint.Status != "PENDING" {
+		t.Errorf("Expected the existing endpoint pe-1, got %+v", endpoint)
+	}
+	if posts != 1 {
+		t.Errorf("Expected 1 POST request, got %d", posts)
+	}
+}
+
+func TestClient_NonIdempotentWithoutLookup(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		expectedPosts int
+	}{
+		{name: "server error is not retried", status: http.StatusInternalServerError, expectedPosts: 1},
+		{name: "unavailable is retried", status: http.StatusServiceUnavailable, expectedPosts: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var posts int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				posts++
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := newReconcileTestClient(t, server)
+			_, err := client.CreateProject(&models.OpenapiCreateProjectReq{Name: stringPtr("my-project")})
+			var apiErr errors.APIError
+			if !stderrors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Errorf("Expected API error with status %d, got %v", tt.status, err)
+			}
+			if posts != tt.expectedPosts {
+				t.Errorf("Expected %d POST requests, got %d", tt.expectedPosts, posts)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteReconcile(t *testing.T) {
+	var deletes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deletes++
+		if deletes == 1 {
+			w.WriteHeader(http.StatusGatewayTimeout)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 49900002, "message": "cluster not found"})
+	}))
+	defer server.Close()
+
+	client := newReconcileTestClient(t, server)
+	if err := client.DeleteCluster("project123", "cluster456"); err != nil {
+		t.Errorf("DeleteCluster() unexpected error: %v", err)
+	}
+	if deletes != 2 {
+		t.Errorf("Expected 2 DELETE requests, got %d", deletes)
+	}
+
+	// Without an earlier ambiguous failure a 404 is reported
+	deletes = 1
+	if err := client.DeleteCluster("project123", "cluster456"); err == nil {
+		t.Error("DeleteCluster() expected not found error but got none")
+	}
+}
+
+func TestMayHaveBeenProcessed(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "rate limited", err: errors.APIError{StatusCode: 429}, expected: false},
+		{name: "unavailable", err: errors.APIError{StatusCode: 503}, expected: false},
+		{name: "bad gateway", err: errors.APIError{StatusCode: 502}, expected: true},
+		{name: "gateway timeout", err: errors.APIError{StatusCode: 504}, expected: true},
+		{name: "bad request", err: errors.APIError{StatusCode: 400}, expected: false},
+		{name: "connection refused", err: fmt.Errorf("request failed: %w", &net.OpError{Op: "dial", Err: fmt.Errorf("connection refused")}), expected: false},
+		{name: "connection reset", err: &net.OpError{Op: "read", Err: fmt.Errorf("connection reset by peer")}, expected: true},
+		{name: "timeout", err: fmt.Errorf("context deadline exceeded (Client.Timeout exceeded while awaiting headers)"), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := mayHaveBeenProcessed(tt.err); result != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
@@ -2,27 +2,36 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
+	"time"
 
+	"github.com/5st7/tidb-cloud-go/pkg/errors"
 	"github.com/5st7/tidb-cloud-go/pkg/models"
 )
 
-// ListRestores lists all restore tasks in a project
+// ListRestores lists the first page of restore tasks in a project
 func (c *Client) ListRestores(projectID string) (*models.OpenapiListRestoreOfProjectResp, error) {
+	return c.ListRestoresWithOptions(context.Background(), projectID, nil)
+}
+
+// ListRestoresWithOptions lists one page of restore tasks in a project
+func (c *Client) ListRestoresWithOptions(ctx context.Context, projectID string, opts *ListOptions) (*models.OpenapiListRestoreOfProjectResp, error) {
 	if projectID == "" {
-		return nil, fmt.Errorf("project ID is required")
+		return nil, errors.Required("project ID")
 	}
 
-	url := fmt.Sprintf("%s/api/%s/projects/%s/restores", c.baseURL, APIVersion, projectID)
+	url := withListOptions(fmt.Sprintf("%s/api/%s/projects/%s/restores", c.baseURL, c.apiVersion, projectID), opts)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.doRequest(req)
+	resp, err := c.doRequestWithRetry(ctx, OpListRestores, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -40,23 +49,39 @@ func (c *Client) ListRestores(projectID string) (*models.OpenapiListRestoreOfPro
 	return &restores, nil
 }
 
+// AllRestores iterates over every restore task in a project, page by page
+func (c *Client) AllRestores(ctx context.Context, projectID string) iter.Seq2[*models.OpenapiListRestoreRespItem, error] {
+	return paginate(ctx, func(ctx context.Context, opts *ListOptions) ([]*models.OpenapiListRestoreRespItem, int64, error) {
+		resp, err := c.ListRestoresWithOptions(ctx, projectID, opts)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp.Items, totalValue(resp.Total), nil
+	})
+}
+
 // GetRestore gets a restore task by ID
 func (c *Client) GetRestore(projectID, restoreID string) (*models.OpenapiGetRestoreResp, error) {
+	return c.GetRestoreWithContext(context.Background(), projectID, restoreID)
+}
+
+// GetRestoreWithContext gets a restore task by ID using ctx for cancellation and deadlines
+func (c *Client) GetRestoreWithContext(ctx context.Context, projectID, restoreID string) (*models.OpenapiGetRestoreResp, error) {
 	if projectID == "" {
-		return nil, fmt.Errorf("project ID is required")
+		return nil, errors.Required("project ID")
 	}
 	if restoreID == "" {
-		return nil, fmt.Errorf("restore ID is required")
+		return nil, errors.Required("restore ID")
 	}
 
-	url := fmt.Sprintf("%s/api/%s/projects/%s/restores/%s", c.baseURL, APIVersion, projectID, restoreID)
+	url := fmt.Sprintf("%s/api/%s/projects/%s/restores/%s", c.baseURL, c.apiVersion, projectID, restoreID)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.doRequest(req)
+	resp, err := c.doRequestWithRetry(ctx, OpGetRestore, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -76,14 +101,19 @@ func (c *Client) GetRestore(projectID, restoreID string) (*models.OpenapiGetRest
 
 // CreateRestore creates a new restore task
 func (c *Client) CreateRestore(projectID string, req *models.OpenapiCreateRestoreReq) (*models.OpenapiCreateRestoreResp, error) {
+	return c.CreateRestoreWithContext(context.Background(), projectID, req)
+}
+
+// CreateRestoreWithContext creates a new restore task using ctx for cancellation and deadlines
+func (c *Client) CreateRestoreWithContext(ctx context.Context, projectID string, req *models.OpenapiCreateRestoreReq) (*models.OpenapiCreateRestoreResp, error) {
 	if projectID == "" {
-		return nil, fmt.Errorf("project ID is required")
+		return nil, errors.Required("project ID")
 	}
 	if req == nil {
-		return nil, fmt.Errorf("request is required")
+		return nil, errors.Required("request")
 	}
 
-	url := fmt.Sprintf("%s/api/%s/projects/%s/restores", c.baseURL, APIVersion, projectID)
+	url := fmt.Sprintf("%s/api/%s/projects/%s/restores", c.baseURL, c.apiVersion, projectID)
 
 	reqBody, err := json.Marshal(req)
 	if err != nil {
@@ -96,10 +126,25 @@ func (c *Client) CreateRestore(projectID string, req *models.OpenapiCreateRestor
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.doRequest(httpReq)
+	// Before resending, look for the restore in case an earlier attempt created it
+	since := time.Now()
+	var created *models.OpenapiCreateRestoreResp
+	var reconcile reconcileFunc
+	if req.Name != nil {
+		reconcile = func(ctx context.Context) (bool, error) {
+			var err error
+			created, err = c.findCreatedRestore(ctx, projectID, req, since)
+			return created != nil, err
+		}
+	}
+
+	resp, reconciled, err := c.doRequestWithReconcile(ctx, OpCreateRestore, httpReq, reconcile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
+	if reconciled {
+		return created, nil
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
@@ -0,0 +1,360 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/5st7/tidb-cloud-go/pkg/errors"
+	"github.com/5st7/tidb-cloud-go/pkg/models"
+	"github.com/5st7/tidb-cloud-go/pkg/retry"
+)
+
+const (
+	// DefaultWaitInterval is the delay between the first and second poll of a waiter
+	DefaultWaitInterval = 10 * time.Second
+	// DefaultWaitMaxInterval caps the delay between polls of a waiter
+	DefaultWaitMaxInterval = time.Minute
+)
+
+// WaitOptions configures how a waiter polls. A nil *WaitOptions uses the defaults.
+type WaitOptions struct {
+	// Interval is the delay after the first poll, which is immediate; it
+	// doubles after each poll up to MaxInterval. Zero uses DefaultWaitInterval.
+	Interval time.Duration
+	// MaxInterval caps the delay between polls. Zero uses DefaultWaitMaxInterval.
+	MaxInterval time.Duration
+	// Timeout bounds the whole wait in addition to ctx; zero waits until ctx is done
+	Timeout time.Duration
+	// OnProgress, if set, is called after every poll
+	OnProgress func(WaitProgress)
+}
+
+// WaitProgress describes a poll of a waiter.
+type WaitProgress struct {
+	// Resource is the kind of resource polled: "cluster", "backup", "restore",
+	// "private endpoint service" or "private endpoint"
+	Resource string
+	// Poll is the 1-based number of the poll
+	Poll int
+	// Elapsed is the time since the wait started
+	Elapsed time.Duration
+	// Status is the status of the resource reported by the poll
+	Status string
+}
+
+// ClusterFailedError is returned by WaitForClusterStatus when the cluster
+// enters a failure state, such as UNAVAILABLE, instead of the target status.
+type ClusterFailedError struct {
+	ProjectID string
+	ClusterID string
+	Status    models.OpenapiClusterStatus
+	Target    models.OpenapiClusterStatus
+}
+
+// Error implements the error interface.
+func (e *ClusterFailedError) Error() string {
+	return fmt.Sprintf("cluster %s entered status %s while waiting for %s", e.ClusterID, e.Status, e.Target)
+}
+
+// clusterFailureStatuses are the statuses a cluster does not leave on its own.
+var clusterFailureStatuses = map[models.OpenapiClusterStatus]bool{
+	models.OpenapiClusterStatusUNAVAILABLE: true,
+}
+
+// WaitForClusterStatus polls GetCluster until the cluster reaches target,
+// e.g. AVAILABLE after CreateCluster or a resume, or PAUSED after a pause.
+// The delay between polls grows as configured by opts.
+//
+// Parameters:
+//   - ctx: Context bounding the wait
+//   - projectID: The ID of the project containing the cluster
+//   - clusterID: The ID of the cluster
+//   - target: The status to wait for
+//   - opts: Polling options; nil uses the defaults
+//
+// Returns:
+//   - *models.OpenapiClusterItem: The cluster once it has reached target
+//   - error: A *ClusterFailedError if the cluster enters a failure state such
+//     as UNAVAILABLE, or an error if a poll fails or the wait times out
+func (c *Client) WaitForClusterStatus(ctx context.Context, projectID, clusterID string, target models.OpenapiClusterStatus, opts *WaitOptions) (*models.OpenapiClusterItem, error) {
+	if projectID == "" {
+		return nil, errors.Required("project ID")
+	}
+	if clusterID == "" {
+		return nil, errors.Required("cluster ID")
+	}
+	if target == "" {
+		return nil, errors.Required("target status")
+	}
+
+	var cluster *models.OpenapiClusterItem
+	err := wait(ctx, "cluster", opts, func(ctx context.Context) (string, bool, error) {
+		var err error
+		cluster, err = c.GetClusterWithContext(ctx, projectID, clusterID)
+		if err != nil {
+			return "", false, err
+		}
+
+		status := clusterStatus(cluster)
+		if status == target {
+			return string(status), true, nil
+		}
+		if clusterFailureStatuses[status] {
+			return string(status), false, &ClusterFailedError{ProjectID: projectID, ClusterID: clusterID, Status: status, Target: target}
+		}
+		return string(status), false, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed waiting for cluster %s to become %s: %w", clusterID, target, err)
+	}
+
+	return cluster, nil
+}
+
+// WaitForClusterDeleted polls GetCluster after DeleteCluster until the
+// cluster is gone, which the API reports with a 404.
+//
+// Parameters:
+//   - ctx: Context bounding the wait
+//   - projectID: The ID of the project containing the cluster
+//   - clusterID: The ID of the deleted cluster
+//   - opts: Polling options; nil uses the defaults
+//
+// Returns:
+//   - error: An error if a poll fails or the wait times out
+func (c *Client) WaitForClusterDeleted(ctx context.Context, projectID, clusterID string, opts *WaitOptions) error {
+	if projectID == "" {
+		return errors.Required("project ID")
+	}
+	if clusterID == "" {
+		return errors.Required("cluster ID")
+	}
+
+	err := wait(ctx, "cluster", opts, func(ctx context.Context) (string, bool, error) {
+		cluster, err := c.GetClusterWithContext(ctx, projectID, clusterID)
+		if err != nil {
+			if stderrors.Is(err, errors.ErrNotFound) {
+				return "DELETED", true, nil
+			}
+			return "", false, err
+		}
+		return string(clusterStatus(cluster)), false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed waiting for cluster %s to be deleted: %w", clusterID, err)
+	}
+
+	return nil
+}
+
+// BackupFailedError is returned by WaitForBackup when the backup reports FAILED.
+type BackupFailedError struct {
+	ProjectID string
+	ClusterID string
+	BackupID  string
+}
+
+// Error implements the error interface.
+func (e *BackupFailedError) Error() string {
+	return fmt.Sprintf("backup %s of cluster %s failed", e.BackupID, e.ClusterID)
+}
+
+// WaitForBackup polls GetBackup after CreateBackup until the backup reports
+// SUCCESS or FAILED.
+//
+// Parameters:
+//   - ctx: Context bounding the wait
+//   - projectID: The ID of the project containing the cluster
+//   - clusterID: The ID of the backed up cluster
+//   - backupID: The ID of the backup
+//   - opts: Polling options; nil uses the defaults
+//
+// Returns:
+//   - *models.OpenapiGetBackupOfClusterResp: The backup once it has succeeded
+//   - error: A *BackupFailedError if the backup fails, or an error if a poll
+//     fails or the wait times out
+func (c *Client) WaitForBackup(ctx context.Context, projectID, clusterID, backupID string, opts *WaitOptions) (*models.OpenapiGetBackupOfClusterResp, error) {
+	if projectID == "" {
+		return nil, errors.Required("project ID")
+	}
+	if clusterID == "" {
+		return nil, errors.Required("cluster ID")
+	}
+	if backupID == "" {
+		return nil, errors.Required("backup ID")
+	}
+
+	var backup *models.OpenapiGetBackupOfClusterResp
+	err := wait(ctx, "backup", opts, func(ctx context.Context) (string, bool, error) {
+		var err error
+		backup, err = c.GetBackupWithContext(ctx, projectID, clusterID, backupID)
+		if err != nil {
+			return "", false, err
+		}
+
+		var status string
+		if backup.Status != nil && backup.Status.BackupStatus != nil {
+			status = *backup.Status.BackupStatus
+		}
+		switch status {
+		case models.OpenapiBackupStatusSUCCESS:
+			return status, true, nil
+		case models.OpenapiBackupStatusFAILED:
+			return status, false, &BackupFailedError{ProjectID: projectID, ClusterID: clusterID, BackupID: backupID}
+		}
+		return status, false, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed waiting for backup %s: %w", backupID, err)
+	}
+
+	return backup, nil
+}
+
+// RestoreFailedError is returned by WaitForRestore when the restore reports FAILED.
+type RestoreFailedError struct {
+	ProjectID string
+	RestoreID string
+}
+
+// Error implements the error interface.
+func (e *RestoreFailedError) Error() string {
+	return fmt.Sprintf("restore %s failed", e.RestoreID)
+}
+
+// RestoreResult is the outcome of a successful WaitForRestore.
+type RestoreResult struct {
+	// Restore is the restore task once it has succeeded
+	Restore *models.OpenapiGetRestoreResp
+	// Cluster is the restored cluster once it is AVAILABLE
+	Cluster *models.OpenapiClusterItem
+}
+
+// WaitForRestore polls GetRestore after CreateRestore until the restore
+// reports SUCCESS or FAILED, then polls the restored cluster until it is
+// AVAILABLE. The timeout of opts bounds both stages together.
+//
+// Parameters:
+//   - ctx: Context bounding the wait
+//   - projectID: The ID of the project containing the restore
+//   - restoreID: The ID of the restore
+//   - opts: Polling options; nil uses the defaults
+//
+// Returns:
+//   - *RestoreResult: The restore and the restored cluster
+//   - error: A *RestoreFailedError if the restore fails, a *ClusterFailedError
+//     if the restored cluster becomes unavailable, or an error if a poll fails
+//     or the wait times out
+func (c *Client) WaitForRestore(ctx context.Context, projectID, restoreID string, opts *WaitOptions) (*RestoreResult, error) {
+	if projectID == "" {
+		return nil, errors.Required("project ID")
+	}
+	if restoreID == "" {
+		return nil, errors.Required("restore ID")
+	}
+
+	ctx, stageOpts, cancel := stageWaitOptions(ctx, opts)
+	defer cancel()
+
+	var restore *models.OpenapiGetRestoreResp
+	err := wait(ctx, "restore", stageOpts, func(ctx context.Context) (string, bool, error) {
+		var err error
+		restore, err = c.GetRestoreWithContext(ctx, projectID, restoreID)
+		if err != nil {
+			return "", false, err
+		}
+
+		var status string
+		if restore.Status != nil && restore.Status.RestoreStatus != nil {
+			status = *restore.Status.RestoreStatus
+		}
+		switch status {
+		case models.OpenapiRestoreStatusSUCCESS:
+			return status, true, nil
+		case models.OpenapiRestoreStatusFAILED:
+			return status, false, &RestoreFailedError{ProjectID: projectID, RestoreID: restoreID}
+		}
+		return status, false, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed waiting for restore %s: %w", restoreID, err)
+	}
+
+	if restore.ClusterInfo == nil || restore.ClusterInfo.ID == nil || *restore.ClusterInfo.ID == "" {
+		return nil, fmt.Errorf("restore %s succeeded without reporting the restored cluster", restoreID)
+	}
+
+	cluster, err := c.WaitForClusterStatus(ctx, projectID, *restore.ClusterInfo.ID, models.OpenapiClusterStatusAVAILABLE, stageOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed waiting for restore %s: %w", restoreID, err)
+	}
+
+	return &RestoreResult{Restore: restore, Cluster: cluster}, nil
+}
+
+// clusterStatus returns the status of cluster, or "" if it is not reported.
+func clusterStatus(cluster *models.OpenapiClusterItem) models.OpenapiClusterStatus {
+	if cluster.Status == nil || cluster.Status.ClusterStatus == nil {
+		return ""
+	}
+	return *cluster.Status.ClusterStatus
+}
+
+// stageWaitOptions applies the timeout of opts to ctx once, so that it bounds
+// a wait made of several stages, and returns the options for each stage.
+func stageWaitOptions(ctx context.Context, opts *WaitOptions) (context.Context, *WaitOptions, context.CancelFunc) {
+	if opts == nil || opts.Timeout <= 0 {
+		return ctx, opts, func() {}
+	}
+	o := *opts
+	o.Timeout = 0
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	return ctx, &o, cancel
+}
+
+// wait calls poll after growing delays until it reports done or fails, or
+// ctx or the timeout of opts ends the wait. The first poll is immediate.
+// Progress is reported for the named resource.
+func wait(ctx context.Context, resource string, opts *WaitOptions, poll func(ctx context.Context) (status string, done bool, err error)) error {
+	var o WaitOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Interval <= 0 {
+		o.Interval = DefaultWaitInterval
+	}
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = DefaultWaitMaxInterval
+	}
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	var status string
+	for n := 1; ; n++ {
+		s, done, err := poll(ctx)
+		if err != nil {
+			return err
+		}
+		status = s
+		if o.OnProgress != nil {
+			o.OnProgress(WaitProgress{Resource: resource, Poll: n, Elapsed: time.Since(start), Status: status})
+		}
+		if done {
+			return nil
+		}
+
+		timer := time.NewTimer(retry.ExponentialBackoff(n, o.Interval, o.MaxInterval, 0))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("last status %q: %w", status, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
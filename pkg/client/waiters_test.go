@@ -0,0 +1,370 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/5st7/tidb-cloud-go/pkg/auth"
+	"github.com/5st7/tidb-cloud-go/pkg/models"
+)
+
+// fastWait polls every millisecond, recording progress.
+func fastWait(progress *[]WaitProgress) *WaitOptions {
+	return &WaitOptions{
+		Interval:    time.Millisecond,
+		MaxInterval: time.Millisecond,
+		Timeout:     time.Second,
+		OnProgress:  func(p WaitProgress) { *progress = append(*progress, p) },
+	}
+}
+
+// newClusterStatusServer serves GetCluster with the given statuses in turn;
+// an empty status answers 404.
+func newClusterStatusServer(t *testing.T, statuses ...models.OpenapiClusterStatus) (*Client, func()) {
+	t.Helper()
+	var polls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[min(polls, len(statuses)-1)]
+		polls++
+		if status == "" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": 49900002, "message": "cluster not found"})
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(&models.OpenapiClusterItem{
+			ID:     stringPtr("cluster456"),
+			Status: &models.OpenapiClusterItemStatus{ClusterStatus: clusterStatusPtr(status)},
+		})
+	}))
+
+	client, err := NewClient("", "", WithBaseURL(server.URL), WithAuthenticator(mustBearer(t, auth.StaticToken("abc123"))))
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+	return client, server.Close
+}
+
+func TestClient_WaitForClusterStatus(t *testing.T) {
+	tests := []struct {
+		name             string
+		statuses         []models.OpenapiClusterStatus
+		target           models.OpenapiClusterStatus
+		timeout          time.Duration
+		expectedError    bool
+		expectedFailed   bool
+		expectedProgress []string
+	}{
+		{
+			name:             "becomes available",
+			statuses:         []models.OpenapiClusterStatus{models.OpenapiClusterStatusCREATING, models.OpenapiClusterStatusCREATING, models.OpenapiClusterStatusAVAILABLE},
+			target:           models.OpenapiClusterStatusAVAILABLE,
+			expectedProgress: []string{"CREATING", "CREATING", "AVAILABLE"},
+		},
+		{
+			name:             "pauses",
+			statuses:         []models.OpenapiClusterStatus{models.OpenapiClusterStatusPAUSING, models.OpenapiClusterStatusPAUSED},
+			target:           models.OpenapiClusterStatusPAUSED,
+			expectedProgress: []string{"PAUSING", "PAUSED"},
+		},
+		{
+			name:             "becomes unavailable",
+			statuses:         []models.OpenapiClusterStatus{models.OpenapiClusterStatusMODIFYING, models.OpenapiClusterStatusUNAVAILABLE},
+			target:           models.OpenapiClusterStatusAVAILABLE,
+			expectedError:    true,
+			expectedFailed:   true,
+			expectedProgress: []string{"MODIFYING"},
+		},
+		{
+			name:          "times out",
+			statuses:      []models.OpenapiClusterStatus{models.OpenapiClusterStatusRESUMING},
+			target:        models.OpenapiClusterStatusAVAILABLE,
+			timeout:       20 * time.Millisecond,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, closeServer := newClusterStatusServer(t, tt.statuses...)
+			defer closeServer()
+
+			var progress []WaitProgress
+			opts := fastWait(&progress)
+			if tt.timeout > 0 {
+				opts.Timeout = tt.timeout
+			}
+
+			cluster, err := client.WaitForClusterStatus(context.Background(), "project123", "cluster456", tt.target, opts)
+
+			if tt.expectedError {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				var failed *ClusterFailedError
+				if stderrors.As(err, &failed) != tt.expectedFailed {
+					t.Errorf("Expected ClusterFailedError %v, got %v", tt.expectedFailed, err)
+				}
+				if tt.expectedFailed && failed.Status != models.OpenapiClusterStatusUNAVAILABLE {
+					t.Errorf("Expected failure status UNAVAILABLE, got %s", failed.Status)
+				}
+				if !tt.expectedFailed && !stderrors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("Expected deadline exceeded, got %v", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if clusterStatus(cluster) != tt.target {
+					t.Errorf("Expected status %s, got %s", tt.target, clusterStatus(cluster))
+				}
+			}
+
+			if tt.expectedProgress != nil {
+				if len(progress) != len(tt.expectedProgress) {
+					t.Fatalf("Expected %d progress reports, got %d", len(tt.expectedProgress), len(progress))
+				}
+				for i, p := range progress {
+					if p.Poll != i+1 || p.Status != tt.expectedProgress[i] {
+						t.Errorf("Progress %d = %+v, want poll %d status %s", i, p, i+1, tt.expectedProgress[i])
+					}
+				}
+			}
+		})
+	}
+
+	client, closeServer := newClusterStatusServer(t, models.OpenapiClusterStatusAVAILABLE)
+	defer closeServer()
+	if _, err := client.WaitForClusterStatus(context.Background(), "project123", "cluster456", "", nil); err == nil || err.Error() != "target status is required" {
+		t.Errorf("Expected target status is required error, got %v", err)
+	}
+}
+
+func TestWait_Interval(t *testing.T) {
+	var progress []WaitProgress
+	opts := &WaitOptions{
+		Interval:    50 * time.Millisecond,
+		MaxInterval: time.Second,
+		OnProgress:  func(p WaitProgress) { progress = append(progress, p) },
+	}
+
+	var polls int
+	err := wait(context.Background(), "cluster", opts, func(ctx context.Context) (string, bool, error) {
+		polls++
+		return "CREATING", polls == 3, nil
+	})
+	if err != nil {
+		t.Fatalf("wait() unexpected error: %v", err)
+	}
+	if len(progress) != 3 {
+		t.Fatalf("Expected 3 polls, got %d", len(progress))
+	}
+
+	// The first poll is immediate, then the delay starts at Interval and doubles
+	if progress[0].Elapsed >= opts.Interval {
+		t.Errorf("Expected the first poll before %v, got %v", opts.Interval, progress[0].Elapsed)
+	}
+	if gap := progress[1].Elapsed - progress[0].Elapsed; gap < opts.Interval {
+		t.Errorf("Expected at least %v before the second poll, got %v", opts.Interval, gap)
+	}
+	if gap := progress[2].Elapsed - progress[1].Elapsed; gap < 2*opts.Interval {
+		t.Errorf("Expected at least %v before the third poll, got %v", 2*opts.Interval, gap)
+	}
+}
+
+func TestClient_WaitForClusterDeleted(t *testing.T) {
+	client, closeServer := newClusterStatusServer(t, models.OpenapiClusterStatusAVAILABLE, "")
+	defer closeServer()
+
+	var progress []WaitProgress
+	if err := client.WaitForClusterDeleted(context.Background(), "project123", "cluster456", fastWait(&progress)); err != nil {
+		t.Fatalf("WaitForClusterDeleted() unexpected error: %v", err)
+	}
+	if len(progress) != 2 || progress[1].Status != "DELETED" {
+		t.Errorf("Expected 2 polls ending with DELETED, got %+v", progress)
+	}
+}
+
+func TestClient_WaitForBackup(t *testing.T) {
+	tests := []struct {
+		name             string
+		statuses         []string
+		expectedFailed   bool
+		expectedProgress []string
+	}{
+		{
+			name:             "succeeds",
+			statuses:         []string{models.OpenapiBackupStatusPENDING, models.OpenapiBackupStatusRUNNING, models.OpenapiBackupStatusSUCCESS},
+			expectedProgress: []string{"PENDING", "RUNNING", "SUCCESS"},
+		},
+		{
+			name:             "fails",
+			statuses:         []string{models.OpenapiBackupStatusRUNNING, models.OpenapiBackupStatusFAILED},
+			expectedFailed:   true,
+			expectedProgress: []string{"RUNNING"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var polls int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v1beta/projects/project123/clusters/cluster456/backups/backup789" {
+					t.Errorf("Unexpected path %s", r.URL.Path)
+				}
+				status := tt.statuses[min(polls, len(tt.statuses)-1)]
+				polls++
+				w.WriteHeader(http.StatusOK)
+				json.NewEncoder(w).Encode(&models.OpenapiGetBackupOfClusterResp{
+					ID:     stringPtr("backup789"),
+					Status: &models.OpenapiGetBackupOfClusterRespStatus{BackupStatus: stringPtr(status)},
+				})
+			}))
+			defer server.Close()
+
+			client, err := NewClient("", "", WithBaseURL(server.URL), WithAuthenticator(mustBearer(t, auth.StaticToken("abc123"))))
+			if err != nil {
+				t.Fatalf("NewClient() unexpected error: %v", err)
+			}
+
+			var progress []WaitProgress
+			backup, err := client.WaitForBackup(context.Background(), "project123", "cluster456", "backup789", fastWait(&progress))
+
+			if tt.expectedFailed {
+				var failed *BackupFailedError
+				if !stderrors.As(err, &failed) {
+					t.Fatalf("Expected BackupFailedError, got %v", err)
+				}
+				if failed.BackupID != "backup789" {
+					t.Errorf("Expected backup ID backup789, got %s", failed.BackupID)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if *backup.Status.BackupStatus != models.OpenapiBackupStatusSUCCESS {
+					t.Errorf("Expected status SUCCESS, got %s", *backup.Status.BackupStatus)
+				}
+			}
+
+			if len(progress) != len(tt.expectedProgress) {
+				t.Fatalf("Expected %d progress reports, got %d", len(tt.expectedProgress), len(progress))
+			}
+			for i, p := range progress {
+				if p.Resource != "backup" || p.Status != tt.expectedProgress[i] {
+					t.Errorf("Progress %d = %+v, want backup status %s", i, p, tt.expectedProgress[i])
+				}
+			}
+		})
+	}
+}
+
+func TestClient_WaitForRestore(t *testing.T) {
+	tests := []struct {
+		name             string
+		restoreStatuses  []string
+		clusterStatuses  []models.OpenapiClusterStatus
+		noCluster        bool
+		expectedError    bool
+		expectedFailed   bool
+		expectedProgress []string
+	}{
+		{
+			name:             "restored cluster becomes available",
+			restoreStatuses:  []string{models.OpenapiRestoreStatusRUNNING, models.OpenapiRestoreStatusSUCCESS},
+			clusterStatuses:  []models.OpenapiClusterStatus{models.OpenapiClusterStatusCREATING, models.OpenapiClusterStatusAVAILABLE},
+			expectedProgress: []string{"restore RUNNING", "restore SUCCESS", "cluster CREATING", "cluster AVAILABLE"},
+		},
+		{
+			name:             "restore fails",
+			restoreStatuses:  []string{models.OpenapiRestoreStatusPENDING, models.OpenapiRestoreStatusFAILED},
+			expectedError:    true,
+			expectedFailed:   true,
+			expectedProgress: []string{"restore PENDING"},
+		},
+		{
+			name:            "no restored cluster",
+			restoreStatuses: []string{models.OpenapiRestoreStatusSUCCESS},
+			noCluster:       true,
+			expectedError:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var restorePolls, clusterPolls int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/v1beta/projects/project123/restores/restore789":
+					status := tt.restoreStatuses[min(restorePolls, len(tt.restoreStatuses)-1)]
+					restorePolls++
+					restore := &models.OpenapiGetRestoreResp{
+						ID:     stringPtr("restore789"),
+						Status: &models.OpenapiGetRestoreRespStatus{RestoreStatus: stringPtr(status)},
+					}
+					if !tt.noCluster {
+						restore.ClusterInfo = &models.OpenapiClusterInfoOfRestore{ID: stringPtr("cluster456")}
+					}
+					w.WriteHeader(http.StatusOK)
+					json.NewEncoder(w).Encode(restore)
+				case "/api/v1beta/projects/project123/clusters/cluster456":
+					status := tt.clusterStatuses[min(clusterPolls, len(tt.clusterStatuses)-1)]
+					clusterPolls++
+					w.WriteHeader(http.StatusOK)
+					json.NewEncoder(w).Encode(&models.OpenapiClusterItem{
+						ID:     stringPtr("cluster456"),
+						Status: &models.OpenapiClusterItemStatus{ClusterStatus: clusterStatusPtr(status)},
+					})
+				default:
+					t.Errorf("Unexpected path %s", r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			client, err := NewClient("", "", WithBaseURL(server.URL), WithAuthenticator(mustBearer(t, auth.StaticToken("abc123"))))
+			if err != nil {
+				t.Fatalf("NewClient() unexpected error: %v", err)
+			}
+
+			var progress []WaitProgress
+			result, err := client.WaitForRestore(context.Background(), "project123", "restore789", fastWait(&progress))
+
+			if tt.expectedError {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				var failed *RestoreFailedError
+				if stderrors.As(err, &failed) != tt.expectedFailed {
+					t.Errorf("Expected RestoreFailedError %v, got %v", tt.expectedFailed, err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if *result.Restore.ID != "restore789" {
+					t.Errorf("Expected restore restore789, got %s", *result.Restore.ID)
+				}
+				if clusterStatus(result.Cluster) != models.OpenapiClusterStatusAVAILABLE {
+					t.Errorf("Expected cluster AVAILABLE, got %s", clusterStatus(result.Cluster))
+				}
+			}
+
+			if tt.expectedProgress != nil {
+				if len(progress) != len(tt.expectedProgress) {
+					t.Fatalf("Expected %d progress reports, got %+v", len(tt.expectedProgress), progress)
+				}
+				for i, p := range progress {
+					if got := p.Resource + " " + p.Status; got != tt.expectedProgress[i] {
+						t.Errorf("Progress %d = %s, want %s", i, got, tt.expectedProgress[i])
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package errors
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Code is a TiDB Cloud business error code, the code field of an error
+// response. Unlike the HTTP status, it identifies why the request failed.
+type Code int64
+
+// Known TiDB Cloud error codes. These are the codes the API reference
+// documents; most failures carry the generic CodeRequestFailed and are
+// classified by their details and HTTP status instead, and further codes can
+// be added with RegisterCode.
+const (
+	// CodeRequestFailed is the generic code of a failed request, such as one
+	// with an unknown public key; the message describes the problem
+	CodeRequestFailed Code = 49900001
+	// CodeRateLimitExceeded is returned with 429 when an API key exceeds its
+	// limit of requests per minute
+	CodeRateLimitExceeded Code = 49900007
+)
+
+// Category groups error codes by how a caller can react to them.
+type Category int
+
+const (
+	// CategoryUnknown is the category of codes missing from the catalog, and
+	// of generic codes that do not tell why a request failed
+	CategoryUnknown Category = iota
+	// CategoryInvalidRequest is a request the API rejects whatever the state
+	// of the resources, e.g. a malformed field
+	CategoryInvalidRequest
+	// CategoryQuotaExceeded is a request that would exceed a quota of the
+	// organization or project, e.g. the number of clusters or nodes
+	CategoryQuotaExceeded
+	// CategoryInvalidState is an operation the resource does not allow in its
+	// current state, e.g. scaling a cluster that is still being created
+	CategoryInvalidState
+	// CategoryConflict is a request that conflicts with an existing resource,
+	// e.g. a cluster name that is already taken
+	CategoryConflict
+	// CategoryRegionUnavailable is a request for a cloud region, or capacity
+	// in a region, that is not available
+	CategoryRegionUnavailable
+	// CategoryRateLimited is a request rejected by the rate limit
+	CategoryRateLimited
+)
+
+// String returns the name of the category.
+func (c Category) String() string {
+	switch c {
+	case CategoryUnknown:
+		return "unknown"
+	case CategoryInvalidRequest:
+		return "invalid request"
+	case CategoryQuotaExceeded:
+		return "quota exceeded"
+	case CategoryInvalidState:
+		return "invalid state"
+	case CategoryConflict:
+		return "conflict"
+	case CategoryRegionUnavailable:
+		return "region unavailable"
+	case CategoryRateLimited:
+		return "rate limited"
+	default:
+		return fmt.Sprintf("Category(%d)", int(c))
+	}
+}
+
+// CodeInfo describes a known error code.
+type CodeInfo struct {
+	Code        Code
+	Category    Category
+	Description string
+}
+
+var (
+	codesMu sync.RWMutex
+	codes   = map[Code]CodeInfo{
+		CodeRequestFailed: {
+			Code:        CodeRequestFailed,
+			Category:    CategoryUnknown,
+			Description: "The request failed; the message describes the problem",
+		},
+		CodeRateLimitExceeded: {
+			Code:        CodeRateLimitExceeded,
+			Category:    CategoryRateLimited,
+			Description: "The request exceeded the limit of requests per API key per minute",
+		},
+	}
+)
+
+// LookupCode returns the catalog entry of code, or false if it is unknown.
+func LookupCode(code Code) (CodeInfo, bool) {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+	info, ok := codes[code]
+	return info, ok
+}
+
+// RegisterCode adds info to the catalog, replacing any entry for the same
+// code, so that codes not yet known to the SDK can be classified by the
+// helpers of APIError. It is safe for concurrent use.
+func RegisterCode(info CodeInfo) {
+	codesMu.Lock()
+	defer codesMu.Unlock()
+	codes[info.Code] = info
+}
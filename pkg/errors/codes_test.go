@@ -0,0 +1,189 @@
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"testing"
+
+	"github.com/5st7/tidb-cloud-go/pkg/models"
+)
+
+func TestAPIError_Category(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		status   int
+		expected Category
+	}{
+		{
+			name:     "rate limit code",
+			body:     `{"code": 49900007, "message": "The request exceeded the limit of 100 times per apikey per minute."}`,
+			status:   429,
+			expected: CategoryRateLimited,
+		},
+		{
+			name:     "generic code falls back to status",
+			body:     `{"code": 49900001, "message": "cluster name already exists"}`,
+			status:   409,
+			expected: CategoryConflict,
+		},
+		{
+			name: "quota failure detail",
+			body: `{"code": 49900001, "message": "quota exceeded", "details": [
+				{"@type": "type.googleapis.com/google.rpc.QuotaFailure", "violations": [{"subject": "project:1", "description": "at most 10 clusters"}]}
+			]}`,
+			status:   400,
+			expected: CategoryQuotaExceeded,
+		},
+		{
+			name: "precondition failure detail",
+			body: `{"code": 49900001, "message": "cluster is not available", "details": [
+				{"@type": "type.googleapis.com/google.rpc.PreconditionFailure", "violations": [{"type": "STATE", "subject": "cluster:1"}]}
+			]}`,
+			status:   400,
+			expected: CategoryInvalidState,
+		},
+		{
+			name:     "bad request status",
+			body:     `{"code": 49900001, "message": "invalid node_size"}`,
+			status:   400,
+			expected: CategoryInvalidRequest,
+		},
+		{
+			name:     "unknown",
+			body:     `{"code": 49900001, "message": "public_key not found"}`,
+			status:   401,
+			expected: CategoryUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := APIError{StatusCode: tt.status}
+			if err := json.Unmarshal([]byte(tt.body), &apiErr); err != nil {
+				t.Fatalf("Unmarshal() unexpected error: %v", err)
+			}
+
+			if got := apiErr.Category(); got != tt.expected {
+				t.Errorf("Expected category %s, got %s", tt.expected, got)
+			}
+			if apiErr.IsQuotaExceeded() != (tt.expected == CategoryQuotaExceeded) {
+				t.Errorf("IsQuotaExceeded() = %v for category %s", apiErr.IsQuotaExceeded(), tt.expected)
+			}
+			if apiErr.IsInvalidState() != (tt.expected == CategoryInvalidState) {
+				t.Errorf("IsInvalidState() = %v for category %s", apiErr.IsInvalidState(), tt.expected)
+			}
+			if apiErr.IsConflict() != (tt.expected == CategoryConflict) {
+				t.Errorf("IsConflict() = %v for category %s", apiErr.IsConflict(), tt.expected)
+			}
+			if apiErr.IsRegionUnavailable() != (tt.expected == CategoryRegionUnavailable) {
+				t.Errorf("IsRegionUnavailable() = %v for category %s", apiErr.IsRegionUnavailable(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegisterCode(t *testing.T) {
+	const code Code = 49999901
+	if _, ok := LookupCode(code); ok {
+		t.Fatalf("Expected code %d to be unknown", code)
+	}
+
+	RegisterCode(CodeInfo{Code: code, Category: CategoryRegionUnavailable, Description: "Region is out of capacity"})
+	defer func() {
+		codesMu.Lock()
+		delete(codes, code)
+		codesMu.Unlock()
+	}()
+
+	info, ok := LookupCode(code)
+	if !ok || info.Category != CategoryRegionUnavailable {
+		t.Fatalf("LookupCode() = %+v, %v, want region unavailable", info, ok)
+	}
+
+	apiErr := APIError{StatusCode: 400}
+	body := `{"code": 49999901, "message": "region us-west-2 has no capacity for TiKV nodes of size 8C32G", "details": []}`
+	if err := json.Unmarshal([]byte(body), &apiErr); err != nil {
+		t.Fatalf("Unmarshal() unexpected error: %v", err)
+	}
+	if !apiErr.IsRegionUnavailable() {
+		t.Error("Expected registered code to be classified as region unavailable")
+	}
+	if apiErr.Category() == CategoryInvalidRequest {
+		t.Error("Expected the catalog to take precedence over the HTTP status")
+	}
+}
+
+func TestAPIError_ConflictSentinel(t *testing.T) {
+	const code Code = 49999902
+	RegisterCode(CodeInfo{Code: code, Category: CategoryConflict, Description: "Cluster name is already taken"})
+	defer func() {
+		codesMu.Lock()
+		delete(codes, code)
+		codesMu.Unlock()
+	}()
+
+	// ErrConflict matches on the HTTP status, whatever the catalog says
+	precondition := APIError{StatusCode: 409, Details: []*models.ProtobufAny{{Type: TypePreconditionFailure}}}
+	if !stderrors.Is(precondition, ErrConflict) {
+		t.Error("Expected 409 with a PreconditionFailure detail to match ErrConflict")
+	}
+	if precondition.IsConflict() {
+		t.Error("Expected 409 with a PreconditionFailure detail not to be classified as a conflict")
+	}
+
+	registered := APIError{StatusCode: 400, Code: int64(code)}
+	if stderrors.Is(registered, ErrConflict) {
+		t.Error("Expected 400 with a conflict code not to match ErrConflict")
+	}
+	if !registered.IsConflict() {
+		t.Error("Expected 400 with a conflict code to be classified as a conflict")
+	}
+}
+
+func TestAPIError_Detail(t *testing.T) {
+	body := `{"code": 49900001, "message": "quota exceeded", "details": [
+		{"@type": "type.googleapis.com/google.rpc.ErrorInfo", "reason": "CLUSTER_QUOTA", "domain": "tidbcloud.com", "metadata": {"limit": "10"}},
+		{"@type": "type.googleapis.com/google.rpc.QuotaFailure", "violations": [{"subject": "project:1", "description": "at most 10 clusters"}]},
+		"delete unused clusters or contact support"
+	]}`
+
+	var apiErr APIError
+	if err := json.Unmarshal([]byte(body), &apiErr); err != nil {
+		t.Fatalf("Unmarshal() unexpected error: %v", err)
+	}
+
+	var info ErrorInfo
+	if !apiErr.Detail(TypeErrorInfo, &info) {
+		t.Fatal("Expected ErrorInfo detail")
+	}
+	if info.Reason != "CLUSTER_QUOTA" || info.Metadata["limit"] != "10" {
+		t.Errorf("Unexpected ErrorInfo %+v", info)
+	}
+
+	var quota QuotaFailure
+	if !apiErr.Detail(TypeQuotaFailure, &quota) {
+		t.Fatal("Expected QuotaFailure detail")
+	}
+	if len(quota.Violations) != 1 || quota.Violations[0].Subject != "project:1" {
+		t.Errorf("Unexpected QuotaFailure %+v", quota)
+	}
+
+	var badRequest BadRequest
+	if apiErr.Detail(TypeBadRequest, &badRequest) {
+		t.Error("Expected no BadRequest detail")
+	}
+
+	// Details survive a round trip with their type URL
+	data, err := json.Marshal(apiErr)
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error: %v", err)
+	}
+	var decoded APIError
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() unexpected error: %v", err)
+	}
+	if len(decoded.Details) != 3 || decoded.Details[1].Type != TypeQuotaFailure || decoded.Details[2].Message != "delete unused clusters or contact support" {
+		t.Errorf("Unexpected details after round trip: %s", data)
+	}
+}
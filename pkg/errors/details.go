@@ -0,0 +1,85 @@
+package errors
+
+// Type URLs of the google.rpc error details an error response may carry.
+const (
+	TypeErrorInfo           = "type.googleapis.com/google.rpc.ErrorInfo"
+	TypeQuotaFailure        = "type.googleapis.com/google.rpc.QuotaFailure"
+	TypePreconditionFailure = "type.googleapis.com/google.rpc.PreconditionFailure"
+	TypeBadRequest          = "type.googleapis.com/google.rpc.BadRequest"
+	TypeResourceInfo        = "type.googleapis.com/google.rpc.ResourceInfo"
+)
+
+// ErrorInfo describes the cause of an error (google.rpc.ErrorInfo).
+type ErrorInfo struct {
+	Reason   string            `json:"reason,omitempty"`
+	Domain   string            `json:"domain,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// QuotaFailure lists the quotas a request exceeded (google.rpc.QuotaFailure).
+type QuotaFailure struct {
+	Violations []QuotaViolation `json:"violations,omitempty"`
+}
+
+// QuotaViolation is a single exceeded quota.
+type QuotaViolation struct {
+	Subject     string `json:"subject,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// PreconditionFailure lists the preconditions a request did not meet, such as
+// the state of a resource (google.rpc.PreconditionFailure).
+type PreconditionFailure struct {
+	Violations []PreconditionViolation `json:"violations,omitempty"`
+}
+
+// PreconditionViolation is a single unmet precondition.
+type PreconditionViolation struct {
+	Type        string `json:"type,omitempty"`
+	Subject     string `json:"subject,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// BadRequest lists the invalid fields of a request (google.rpc.BadRequest).
+type BadRequest struct {
+	FieldViolations []BadRequestFieldViolation `json:"field_violations,omitempty"`
+}
+
+// BadRequestFieldViolation is a single invalid field.
+type BadRequestFieldViolation struct {
+	Field       string `json:"field,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ResourceInfo describes the resource an error is about (google.rpc.ResourceInfo).
+type ResourceInfo struct {
+	ResourceType string `json:"resource_type,omitempty"`
+	ResourceName string `json:"resource_name,omitempty"`
+	Owner        string `json:"owner,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// Detail decodes the first detail of the response with the given type URL,
+// such as TypeQuotaFailure, into v. It reports whether such a detail was found
+// and decoded.
+//
+//	var quota errors.QuotaFailure
+//	if apiErr.Detail(errors.TypeQuotaFailure, &quota) { ... }
+func (e APIError) Detail(typeURL string, v interface{}) bool {
+	for _, detail := range e.Details {
+		if detail != nil && detail.Type == typeURL {
+			return detail.Decode(v) == nil
+		}
+	}
+	return false
+}
+
+// hasDetail reports whether the response carries a detail with the given type URL.
+func (e APIError) hasDetail(typeURL string) bool {
+	for _, detail := range e.Details {
+		if detail != nil && detail.Type == typeURL {
+			return true
+		}
+	}
+	return false
+}
@@ -4,18 +4,47 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 	"net/http"
+	"time"
+
+	"github.com/5st7/tidb-cloud-go/pkg/models"
+)
+
+// Sentinel errors classify failures independently of how they are wrapped.
+// An APIError matches the sentinel for its HTTP status and a ValidationError
+// matches ErrInvalidArgument, so callers can test any error returned by the
+// SDK with errors.Is:
+//
+//	if errors.Is(err, errors.ErrNotFound) { ... }
+var (
+	// ErrInvalidArgument is matched by 400 responses and client-side validation failures
+	ErrInvalidArgument = stderrors.New("invalid argument")
+	// ErrUnauthorized is matched by 401 responses: credentials are missing or invalid
+	ErrUnauthorized = stderrors.New("unauthorized")
+	// ErrPermissionDenied is matched by 403 responses: the API key lacks a permission
+	ErrPermissionDenied = stderrors.New("permission denied")
+	// ErrNotFound is matched by 404 responses
+	ErrNotFound = stderrors.New("not found")
+	// ErrConflict is matched by 409 responses, e.g. a name that is already taken
+	ErrConflict = stderrors.New("conflict")
+	// ErrRateLimited is matched by 429 responses
+	ErrRateLimited = stderrors.New("rate limited")
+	// ErrServerError is matched by 5xx responses
+	ErrServerError = stderrors.New("server error")
 )
 
 // APIError represents an error returned by the TiDB Cloud API.
 // It includes the HTTP status code, TiDB Cloud specific error code,
-// error message, and optional additional details.
+// error message, and optional additional details, which Detail decodes.
+// RateLimit is set when the response carried rate-limit or Retry-After headers.
 type APIError struct {
-	StatusCode int           `json:"-"`
-	Code       int64         `json:"code,omitempty"`
-	Message    string        `json:"message,omitempty"`
-	Details    []interface{} `json:"details,omitempty"`
+	StatusCode int                   `json:"-"`
+	Code       int64                 `json:"code,omitempty"`
+	Message    string                `json:"message,omitempty"`
+	Details    []*models.ProtobufAny `json:"details,omitempty"`
+	RateLimit  *RateLimit            `json:"-"`
 }
 
 // Error implements the error interface and returns a formatted error message
@@ -24,10 +53,96 @@ func (e APIError) Error() string {
 	return fmt.Sprintf("TiDB Cloud API error (%d): %s (code: %d)", e.StatusCode, e.Message, e.Code)
 }
 
+// Is reports whether target is the sentinel error for the HTTP status of e,
+// so that errors.Is matches an APIError however deeply it is wrapped.
+func (e APIError) Is(target error) bool {
+	switch target {
+	case ErrInvalidArgument:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrPermissionDenied:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServerError:
+		return e.StatusCode >= http.StatusInternalServerError
+	default:
+		return false
+	}
+}
+
 // IsRateLimitError returns true if this is a rate limit error.
 // TiDB Cloud enforces a rate limit of 100 requests per minute per API key.
 func (e APIError) IsRateLimitError() bool {
-	return e.StatusCode == http.StatusTooManyRequests && e.Code == 49900007
+	return e.StatusCode == http.StatusTooManyRequests && Code(e.Code) == CodeRateLimitExceeded
+}
+
+// RetryDelay returns how long the server asked the client to wait before
+// retrying, from the Retry-After or X-Ratelimit-Reset header. It returns false
+// if the response advertised no delay.
+func (e APIError) RetryDelay() (time.Duration, bool) {
+	return e.RateLimit.Delay()
+}
+
+// Category classifies the error by the catalog entry of its code (see
+// LookupCode) or, for codes the catalog does not classify, by its google.rpc
+// details and then by its HTTP status.
+func (e APIError) Category() Category {
+	if info, ok := LookupCode(Code(e.Code)); ok && info.Category != CategoryUnknown {
+		return info.Category
+	}
+
+	switch {
+	case e.hasDetail(TypeQuotaFailure):
+		return CategoryQuotaExceeded
+	case e.hasDetail(TypePreconditionFailure):
+		return CategoryInvalidState
+	case e.hasDetail(TypeBadRequest):
+		return CategoryInvalidRequest
+	}
+
+	switch e.StatusCode {
+	case http.StatusBadRequest:
+		return CategoryInvalidRequest
+	case http.StatusConflict:
+		return CategoryConflict
+	case http.StatusPreconditionFailed:
+		return CategoryInvalidState
+	case http.StatusTooManyRequests:
+		return CategoryRateLimited
+	default:
+		return CategoryUnknown
+	}
+}
+
+// IsQuotaExceeded returns true if the request would exceed a quota of the
+// organization or project, e.g. the number of clusters.
+func (e APIError) IsQuotaExceeded() bool {
+	return e.Category() == CategoryQuotaExceeded
+}
+
+// IsInvalidState returns true if the resource does not allow the operation in
+// its current state, e.g. a cluster that is still being created. Retrying
+// after the resource has settled may succeed.
+func (e APIError) IsInvalidState() bool {
+	return e.Category() == CategoryInvalidState
+}
+
+// IsConflict returns true if the request conflicts with an existing resource,
+// e.g. a name that is already taken.
+func (e APIError) IsConflict() bool {
+	return e.Category() == CategoryConflict
+}
+
+// IsRegionUnavailable returns true if the requested cloud region, or capacity
+// in it, is not available.
+func (e APIError) IsRegionUnavailable() bool {
+	return e.Category() == CategoryRegionUnavailable
 }
 
 // IsRetryable returns true if this error should be retried.
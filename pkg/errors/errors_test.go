@@ -1,7 +1,11 @@
 package errors
 
 import (
+	stderrors "errors"
+	"fmt"
 	"testing"
+
+	"github.com/5st7/tidb-cloud-go/pkg/models"
 )
 
 func TestAPIError_Error(t *testing.T) {
@@ -16,7 +20,7 @@ func TestAPIError_Error(t *testing.T) {
 				StatusCode: 400,
 				Code:       49900001,
 				Message:    "Invalid request",
-				Details:    []interface{}{"field validation failed"},
+				Details:    []*models.ProtobufAny{{Type: TypeBadRequest}},
 			},
 			expected: "TiDB Cloud API error (400): Invalid request (code: 49900001)",
 		},
@@ -162,3 +166,39 @@ func TestAPIError_IsRetryable(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIError_Is(t *testing.T) {
+	sentinels := []error{ErrInvalidArgument, ErrUnauthorized, ErrPermissionDenied, ErrNotFound, ErrConflict, ErrRateLimited, ErrServerError}
+
+	tests := []struct {
+		name       string
+		statusCode int
+		expected   error
+	}{
+		{name: "bad request", statusCode: 400, expected: ErrInvalidArgument},
+		{name: "unauthorized", statusCode: 401, expected: ErrUnauthorized},
+		{name: "forbidden", statusCode: 403, expected: ErrPermissionDenied},
+		{name: "not found", statusCode: 404, expected: ErrNotFound},
+		{name: "conflict", statusCode: 409, expected: ErrConflict},
+		{name: "rate limited", statusCode: 429, expected: ErrRateLimited},
+		{name: "internal server error", statusCode: 500, expected: ErrServerError},
+		{name: "service unavailable", statusCode: 503, expected: ErrServerError},
+		{name: "unprocessable entity", statusCode: 422, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("failed to execute request: %w", APIError{StatusCode: tt.statusCode})
+			for _, sentinel := range sentinels {
+				if got := stderrors.Is(err, sentinel); got != (sentinel == tt.expected) {
+					t.Errorf("errors.Is(%d, %v) = %v, want %v", tt.statusCode, sentinel, got, !got)
+				}
+			}
+
+			var apiErr APIError
+			if !stderrors.As(err, &apiErr) || apiErr.StatusCode != tt.statusCode {
+				t.Errorf("errors.As() did not unwrap the APIError from %v", err)
+			}
+		})
+	}
+}
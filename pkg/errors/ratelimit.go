@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rate-limit headers returned by the TiDB Cloud API.
+const (
+	HeaderRateLimitLimit     = "X-Ratelimit-Limit-Minute"
+	HeaderRateLimitRemaining = "X-Ratelimit-Remaining-Minute"
+	HeaderRateLimitReset     = "X-Ratelimit-Reset"
+	HeaderRetryAfter         = "Retry-After"
+)
+
+// RateLimit holds the rate-limit headers of an API response.
+type RateLimit struct {
+	// Limit is the number of requests allowed per minute, or -1 if not reported
+	Limit int
+	// Remaining is the number of requests left in the current minute, or -1 if not reported
+	Remaining int
+	// Reset is when the current limit resets; zero if not reported
+	Reset time.Time
+	// RetryAfter is the delay requested by the Retry-After header; zero if not reported
+	RetryAfter time.Duration
+}
+
+// ParseRateLimit reads the rate-limit headers of a response received at now.
+// X-Ratelimit-Reset is a number of seconds; Retry-After is a number of seconds
+// or an HTTP date. It returns nil if none of the headers are present.
+func ParseRateLimit(header http.Header, now time.Time) *RateLimit {
+	rl := RateLimit{
+		Limit:     headerInt(header, HeaderRateLimitLimit),
+		Remaining: headerInt(header, HeaderRateLimitRemaining),
+	}
+	found := rl.Limit >= 0 || rl.Remaining >= 0
+
+	if seconds := headerInt(header, HeaderRateLimitReset); seconds >= 0 {
+		rl.Reset = now.Add(time.Duration(seconds) * time.Second)
+		found = true
+	}
+
+	if value := strings.TrimSpace(header.Get(HeaderRetryAfter)); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			rl.RetryAfter = time.Duration(seconds) * time.Second
+			found = true
+		} else if date, err := http.ParseTime(value); err == nil {
+			if date.After(now) {
+				rl.RetryAfter = date.Sub(now)
+			}
+			found = true
+		}
+	}
+
+	if !found {
+		return nil
+	}
+	return &rl
+}
+
+// Delay returns how long the server asked the client to wait: Retry-After if
+// set, otherwise the time until the limit resets. It returns false if the
+// server advertised neither.
+func (r *RateLimit) Delay() (time.Duration, bool) {
+	if r == nil {
+		return 0, false
+	}
+	if r.RetryAfter > 0 {
+		return r.RetryAfter, true
+	}
+	if !r.Reset.IsZero() {
+		return max(time.Until(r.Reset), 0), true
+	}
+	return 0, false
+}
+
+// headerInt returns the non-negative integer value of a header, or -1.
+func headerInt(header http.Header, name string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(header.Get(name)))
+	if err != nil || n < 0 {
+		return -1
+	}
+	return n
+}
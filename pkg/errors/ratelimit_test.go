@@ -0,0 +1,114 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	now := time.Date(2022, 7, 22, 5, 28, 37, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected *RateLimit
+	}{
+		{
+			name:     "no headers",
+			headers:  map[string]string{},
+			expected: nil,
+		},
+		{
+			name: "rate limit exceeded",
+			headers: map[string]string{
+				"X-Ratelimit-Limit-Minute":     "100",
+				"X-Ratelimit-Remaining-Minute": "0",
+				"X-Ratelimit-Reset":            "23",
+			},
+			expected: &RateLimit{Limit: 100, Remaining: 0, Reset: now.Add(23 * time.Second)},
+		},
+		{
+			name:     "retry after seconds",
+			headers:  map[string]string{"Retry-After": "5"},
+			expected: &RateLimit{Limit: -1, Remaining: -1, RetryAfter: 5 * time.Second},
+		},
+		{
+			name:     "retry after date",
+			headers:  map[string]string{"Retry-After": "Fri, 22 Jul 2022 05:28:47 GMT"},
+			expected: &RateLimit{Limit: -1, Remaining: -1, RetryAfter: 10 * time.Second},
+		},
+		{
+			name:     "invalid values",
+			headers:  map[string]string{"X-Ratelimit-Reset": "soon", "Retry-After": "later"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for k, v := range tt.headers {
+				header.Set(k, v)
+			}
+
+			result := ParseRateLimit(header, now)
+			if tt.expected == nil || result == nil {
+				if result != tt.expected {
+					t.Errorf("Expected %v, got %v", tt.expected, result)
+				}
+				return
+			}
+			if *result != *tt.expected {
+				t.Errorf("Expected %+v, got %+v", *tt.expected, *result)
+			}
+		})
+	}
+}
+
+func TestAPIError_RetryDelay(t *testing.T) {
+	tests := []struct {
+		name        string
+		apiError    APIError
+		expectedOK  bool
+		minExpected time.Duration
+		maxExpected time.Duration
+	}{
+		{
+			name:       "no rate limit headers",
+			apiError:   APIError{StatusCode: 503},
+			expectedOK: false,
+		},
+		{
+			name:        "retry after takes precedence",
+			apiError:    APIError{StatusCode: 429, RateLimit: &RateLimit{RetryAfter: 3 * time.Second, Reset: time.Now().Add(time.Minute)}},
+			expectedOK:  true,
+			minExpected: 3 * time.Second,
+			maxExpected: 3 * time.Second,
+		},
+		{
+			name:        "rate limit reset",
+			apiError:    APIError{StatusCode: 429, RateLimit: &RateLimit{Reset: time.Now().Add(10 * time.Second)}},
+			expectedOK:  true,
+			minExpected: 9 * time.Second,
+			maxExpected: 10 * time.Second,
+		},
+		{
+			name:       "reset in the past",
+			apiError:   APIError{StatusCode: 429, RateLimit: &RateLimit{Reset: time.Now().Add(-time.Second)}},
+			expectedOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, ok := tt.apiError.RetryDelay()
+			if ok != tt.expectedOK {
+				t.Fatalf("Expected ok %v, got %v", tt.expectedOK, ok)
+			}
+			if delay < tt.minExpected || delay > tt.maxExpected {
+				t.Errorf("Expected delay in [%v, %v], got %v", tt.minExpected, tt.maxExpected, delay)
+			}
+		})
+	}
+}
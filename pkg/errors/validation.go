@@ -0,0 +1,35 @@
+package errors
+
+import "fmt"
+
+// ValidationError is returned when a request is rejected by the SDK before it
+// is sent, e.g. because a required parameter is empty. It matches
+// ErrInvalidArgument with errors.Is.
+type ValidationError struct {
+	// Field names the invalid parameter as it appears in Message, e.g. "project ID"
+	Field string
+	// Message describes the problem, e.g. "project ID is required"
+	Message string
+}
+
+// Error implements the error interface and returns Message.
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is ErrInvalidArgument.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidArgument
+}
+
+// Required returns a *ValidationError for a required parameter that is
+// missing, e.g. Required("project ID").
+func Required(field string) error {
+	return &ValidationError{Field: field, Message: field + " is required"}
+}
+
+// Invalid returns a *ValidationError for a parameter with an invalid value,
+// with a message formatted according to format.
+func Invalid(field, format string, args ...interface{}) error {
+	return &ValidationError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
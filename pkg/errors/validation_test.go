@@ -0,0 +1,53 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+)
+
+func TestValidationError(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		expectedField   string
+		expectedMessage string
+	}{
+		{
+			name:            "required",
+			err:             Required("project ID"),
+			expectedField:   "project ID",
+			expectedMessage: "project ID is required",
+		},
+		{
+			name:            "invalid",
+			err:             Invalid("API version", "invalid API version %q", "v2"),
+			expectedField:   "API version",
+			expectedMessage: `invalid API version "v2"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err.Error() != tt.expectedMessage {
+				t.Errorf("Expected %q, got %q", tt.expectedMessage, tt.err.Error())
+			}
+
+			wrapped := fmt.Errorf("invalid request: %w", tt.err)
+			if !stderrors.Is(wrapped, ErrInvalidArgument) {
+				t.Error("Expected validation error to match ErrInvalidArgument")
+			}
+			if stderrors.Is(wrapped, ErrNotFound) {
+				t.Error("Expected validation error not to match ErrNotFound")
+			}
+
+			var validationErr *ValidationError
+			if !stderrors.As(wrapped, &validationErr) {
+				t.Fatal("Expected errors.As to find the ValidationError")
+			}
+			if validationErr.Field != tt.expectedField {
+				t.Errorf("Expected field %q, got %q", tt.expectedField, validationErr.Field)
+			}
+		})
+	}
+}
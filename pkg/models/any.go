@@ -0,0 +1,69 @@
+package models
+
+import "encoding/json"
+
+// ProtobufAny is a detail of an error response. The default error schema
+// (protobufAny) describes a message whose type is identified by the @type URL,
+// e.g. "type.googleapis.com/google.rpc.QuotaFailure", with the fields of the
+// message alongside it; the error schemas of most operations describe a plain
+// string instead, which is kept in Message.
+type ProtobufAny struct {
+	Type   string
+	Fields map[string]json.RawMessage
+	// Message is the text of a detail sent as a string rather than a message
+	Message string
+}
+
+// UnmarshalJSON splits the @type URL from the fields of the message, or keeps
+// a string detail in Message. It never fails, so that an unexpected detail
+// cannot hide the code and message of the error response: a detail that is
+// neither is kept as JSON text in Message, and a non-string @type stays
+// among the fields.
+func (a *ProtobufAny) UnmarshalJSON(data []byte) error {
+	*a = ProtobufAny{}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		if err := json.Unmarshal(data, &a.Message); err != nil {
+			a.Message = string(data)
+		}
+		return nil
+	}
+	if raw, ok := fields["@type"]; ok && json.Unmarshal(raw, &a.Type) == nil {
+		delete(fields, "@type")
+	}
+	if len(fields) > 0 {
+		a.Fields = fields
+	}
+	return nil
+}
+
+// MarshalJSON writes the @type URL alongside the fields of the message, or a
+// string detail as a string.
+func (a ProtobufAny) MarshalJSON() ([]byte, error) {
+	if a.Type == "" && len(a.Fields) == 0 && a.Message != "" {
+		return json.Marshal(a.Message)
+	}
+	fields := make(map[string]json.RawMessage, len(a.Fields)+1)
+	for k, v := range a.Fields {
+		fields[k] = v
+	}
+	if a.Type != "" {
+		typ, err := json.Marshal(a.Type)
+		if err != nil {
+			return nil, err
+		}
+		fields["@type"] = typ
+	}
+	return json.Marshal(fields)
+}
+
+// Decode unmarshals the fields of the message into v, e.g. a struct with the
+// fields of the message type.
+func (a *ProtobufAny) Decode(v interface{}) error {
+	data, err := json.Marshal(a.Fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
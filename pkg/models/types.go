@@ -1,7 +1,7 @@
 // Package models contains all request and response types for the TiDB Cloud API.
 // These types are generated based on the OpenAPI specification and provide
 // strong typing for all API operations including projects, clusters, backups,
-// restores, and private endpoints.
+// restores, imports, and private endpoints.
 package models
 
 // Project API models
@@ -17,10 +17,12 @@ type OpenapiListProjectItem struct {
 	ClusterCount    *int64  `json:"cluster_count,omitempty"`
 	UserCount       *int64  `json:"user_count,omitempty"`
 	CreateTimestamp *string `json:"create_timestamp,omitempty"`
+	AwsCmekEnabled  *bool   `json:"aws_cmek_enabled,omitempty"`
 }
 
 type OpenapiCreateProjectReq struct {
-	Name *string `json:"name,omitempty"`
+	Name           *string `json:"name,omitempty"`
+	AwsCmekEnabled *bool   `json:"aws_cmek_enabled,omitempty"`
 }
 
 type OpenapiCreateProjectResp struct {
@@ -28,15 +30,60 @@ type OpenapiCreateProjectResp struct {
 	Name *string `json:"name,omitempty"`
 }
 
+// AWS CMEK API models
+type OpenapiAwsCmekSpec struct {
+	Region *string `json:"region,omitempty"`
+	KmsArn *string `json:"kms_arn,omitempty"`
+}
+
+type OpenapiListAwsCmekResp struct {
+	Items []*OpenapiAwsCmekSpec `json:"items,omitempty"`
+}
+
+type OpenapiCreateAwsCmekReq struct {
+	Specs []*OpenapiAwsCmekSpec `json:"specs,omitempty"`
+}
+
 // Provider Regions API models
 type OpenapiListProviderRegionsResp struct {
 	Items []*OpenapiListProviderRegionsItem `json:"items,omitempty"`
 }
 
 type OpenapiListProviderRegionsItem struct {
-	CloudProvider *string `json:"cloud_provider,omitempty"`
-	Region        *string `json:"region,omitempty"`
-	Available     *bool   `json:"available,omitempty"`
+	ClusterType   *string                  `json:"cluster_type,omitempty"`
+	CloudProvider *string                  `json:"cloud_provider,omitempty"`
+	Region        *string                  `json:"region,omitempty"`
+	Available     *bool                    `json:"available,omitempty"`
+	TiDB          []*OpenapiTiDBProfile    `json:"tidb,omitempty"`
+	TiKV          []*OpenapiTiKVProfile    `json:"tikv,omitempty"`
+	TiFlash       []*OpenapiTiFlashProfile `json:"tiflash,omitempty"`
+}
+
+type OpenapiTiDBProfile struct {
+	NodeSize          *string                   `json:"node_size,omitempty"`
+	NodeQuantityRange *OpenapiNodeQuantityRange `json:"node_quantity_range,omitempty"`
+}
+
+type OpenapiTiKVProfile struct {
+	NodeSize            *string                      `json:"node_size,omitempty"`
+	NodeQuantityRange   *OpenapiNodeQuantityRange    `json:"node_quantity_range,omitempty"`
+	StorageSizeGibRange *OpenapiNodeStorageSizeRange `json:"storage_size_gib_range,omitempty"`
+}
+
+type OpenapiTiFlashProfile struct {
+	NodeSize            *string                      `json:"node_size,omitempty"`
+	NodeQuantityRange   *OpenapiNodeQuantityRange    `json:"node_quantity_range,omitempty"`
+	StorageSizeGibRange *OpenapiNodeStorageSizeRange `json:"storage_size_gib_range,omitempty"`
+}
+
+type OpenapiNodeQuantityRange struct {
+	Min  *int64 `json:"min,omitempty"`
+	Step *int64 `json:"step,omitempty"`
+}
+
+type OpenapiNodeStorageSizeRange struct {
+	Min *int64 `json:"min,omitempty"`
+	Max *int64 `json:"max,omitempty"`
 }
 
 // Cluster API models
@@ -45,8 +92,32 @@ type OpenapiListClustersOfProjectResp struct {
 	Total *int64                `json:"total,omitempty"`
 }
 
+type OpenapiClusterStatus string
+
+const (
+	OpenapiClusterStatusAVAILABLE   OpenapiClusterStatus = "AVAILABLE"
+	OpenapiClusterStatusCREATING    OpenapiClusterStatus = "CREATING"
+	OpenapiClusterStatusMODIFYING   OpenapiClusterStatus = "MODIFYING"
+	OpenapiClusterStatusPAUSED      OpenapiClusterStatus = "PAUSED"
+	OpenapiClusterStatusRESUMING    OpenapiClusterStatus = "RESUMING"
+	OpenapiClusterStatusUNAVAILABLE OpenapiClusterStatus = "UNAVAILABLE"
+	OpenapiClusterStatusIMPORTING   OpenapiClusterStatus = "IMPORTING"
+	OpenapiClusterStatusMAINTAINING OpenapiClusterStatus = "MAINTAINING"
+	OpenapiClusterStatusPAUSING     OpenapiClusterStatus = "PAUSING"
+)
+
+type OpenapiNodeStatus string
+
+const (
+	OpenapiNodeStatusNODESTATUSAVAILABLE   OpenapiNodeStatus = "NODE_STATUS_AVAILABLE"
+	OpenapiNodeStatusNODESTATUSUNAVAILABLE OpenapiNodeStatus = "NODE_STATUS_UNAVAILABLE"
+	OpenapiNodeStatusNODESTATUSCREATING    OpenapiNodeStatus = "NODE_STATUS_CREATING"
+	OpenapiNodeStatusNODESTATUSDELETING    OpenapiNodeStatus = "NODE_STATUS_DELETING"
+)
+
 type OpenapiClusterItem struct {
 	ID                *string                          `json:"id,omitempty"`
+	ProjectID         *string                          `json:"project_id,omitempty"`
 	Name              *string                          `json:"name,omitempty"`
 	ClusterType       *string                          `json:"cluster_type,omitempty"`
 	CloudProvider     *string                          `json:"cloud_provider,omitempty"`
@@ -58,7 +129,45 @@ type OpenapiClusterItem struct {
 }
 
 type OpenapiClusterItemStatus struct {
-	ClusterStatus *string `json:"cluster_status,omitempty"`
+	TiDBVersion       *string                          `json:"tidb_version,omitempty"`
+	ClusterStatus     *OpenapiClusterStatus            `json:"cluster_status,omitempty"`
+	NodeMap           *OpenapiClusterNodeMap           `json:"node_map,omitempty"`
+	ConnectionStrings *OpenapiClusterConnectionStrings `json:"connection_strings,omitempty"`
+}
+
+type OpenapiClusterNodeMap struct {
+	TiDB    []*OpenapiTiDBNodeMap    `json:"tidb,omitempty"`
+	TiKV    []*OpenapiTiKVNodeMap    `json:"tikv,omitempty"`
+	TiFlash []*OpenapiTiFlashNodeMap `json:"tiflash,omitempty"`
+}
+
+type OpenapiTiDBNodeMap struct {
+	NodeName         *string            `json:"node_name,omitempty"`
+	AvailabilityZone *string            `json:"availability_zone,omitempty"`
+	NodeSize         *string            `json:"node_size,omitempty"`
+	VCPUNum          *int64             `json:"vcpu_num,omitempty"`
+	RAMBytes         *string            `json:"ram_bytes,omitempty"`
+	Status           *OpenapiNodeStatus `json:"status,omitempty"`
+}
+
+type OpenapiTiKVNodeMap struct {
+	NodeName         *string            `json:"node_name,omitempty"`
+	AvailabilityZone *string            `json:"availability_zone,omitempty"`
+	NodeSize         *string            `json:"node_size,omitempty"`
+	VCPUNum          *int64             `json:"vcpu_num,omitempty"`
+	RAMBytes         *string            `json:"ram_bytes,omitempty"`
+	StorageSizeGib   *int64             `json:"storage_size_gib,omitempty"`
+	Status           *OpenapiNodeStatus `json:"status,omitempty"`
+}
+
+type OpenapiTiFlashNodeMap struct {
+	NodeName         *string            `json:"node_name,omitempty"`
+	AvailabilityZone *string            `json:"availability_zone,omitempty"`
+	NodeSize         *string            `json:"node_size,omitempty"`
+	VCPUNum          *int64             `json:"vcpu_num,omitempty"`
+	RAMBytes         *string            `json:"ram_bytes,omitempty"`
+	StorageSizeGib   *int64             `json:"storage_size_gib,omitempty"`
+	Status           *OpenapiNodeStatus `json:"status,omitempty"`
 }
 
 type OpenapiGetClusterConfig struct {
@@ -93,8 +202,9 @@ type OpenapiTiFlashComponent struct {
 }
 
 type OpenapiClusterConnectionStrings struct {
-	DefaultUser *string                    `json:"default_user,omitempty"`
-	Standard    *OpenapiStandardConnection `json:"standard,omitempty"`
+	DefaultUser *string                      `json:"default_user,omitempty"`
+	Standard    *OpenapiStandardConnection   `json:"standard,omitempty"`
+	VPCPeering  *OpenapiVPCPeeringConnection `json:"vpc_peering,omitempty"`
 }
 
 type OpenapiStandardConnection struct {
@@ -102,6 +212,11 @@ type OpenapiStandardConnection struct {
 	Port *int64  `json:"port,omitempty"`
 }
 
+type OpenapiVPCPeeringConnection struct {
+	Host *string `json:"host,omitempty"`
+	Port *int64  `json:"port,omitempty"`
+}
+
 type OpenapiIpAccessListItem struct {
 	CIDR        *string `json:"cidr,omitempty"`
 	Description *string `json:"description,omitempty"`
@@ -182,6 +297,14 @@ type OpenapiListBackupItemStatus struct {
 	BackupStatus *string `json:"backup_status,omitempty"`
 }
 
+// Values of backup_status
+const (
+	OpenapiBackupStatusPENDING = "PENDING"
+	OpenapiBackupStatusRUNNING = "RUNNING"
+	OpenapiBackupStatusFAILED  = "FAILED"
+	OpenapiBackupStatusSUCCESS = "SUCCESS"
+)
+
 type OpenapiCreateBackupReq struct {
 	Name        *string `json:"name,omitempty"`
 	Description *string `json:"description,omitempty"`
@@ -228,6 +351,14 @@ type OpenapiListRestoreRespItemStatus struct {
 	RestoreStatus *string `json:"restore_status,omitempty"`
 }
 
+// Values of restore_status
+const (
+	OpenapiRestoreStatusPENDING = "PENDING"
+	OpenapiRestoreStatusRUNNING = "RUNNING"
+	OpenapiRestoreStatusFAILED  = "FAILED"
+	OpenapiRestoreStatusSUCCESS = "SUCCESS"
+)
+
 type OpenapiClusterInfoOfRestore struct {
 	ID   *string `json:"id,omitempty"`
 	Name *string `json:"name,omitempty"`
@@ -259,6 +390,10 @@ type OpenapiGetRestoreRespStatus struct {
 
 // Private Endpoint API models
 type OpenapiGetPrivateEndpointServiceResp struct {
+	PrivateEndpointService *OpenapiPrivateEndpointService `json:"private_endpoint_service,omitempty"`
+}
+
+type OpenapiPrivateEndpointService struct {
 	CloudProvider *string  `json:"cloud_provider,omitempty"`
 	Name          *string  `json:"name,omitempty"`
 	Status        *string  `json:"status,omitempty"`
@@ -267,16 +402,31 @@ type OpenapiGetPrivateEndpointServiceResp struct {
 	AzIDs         []string `json:"az_ids,omitempty"`
 }
 
+// Values of the private endpoint service status
+const (
+	OpenapiPrivateEndpointServiceStatusCREATING = "CREATING"
+	OpenapiPrivateEndpointServiceStatusACTIVE   = "ACTIVE"
+	OpenapiPrivateEndpointServiceStatusDELETING = "DELETING"
+)
+
+// Values of the private endpoint status
+const (
+	OpenapiPrivateEndpointStatusPENDING  = "PENDING"
+	OpenapiPrivateEndpointStatusACTIVE   = "ACTIVE"
+	OpenapiPrivateEndpointStatusDELETING = "DELETING"
+	OpenapiPrivateEndpointStatusFAILED   = "FAILED"
+)
+
 type OpenapiListPrivateEndpointsResp struct {
-	Items []*OpenapiPrivateEndpointItem `json:"items,omitempty"`
-	Total *int64                        `json:"total,omitempty"`
+	Endpoints []*OpenapiPrivateEndpointItem `json:"endpoints,omitempty"`
 }
 
 type OpenapiPrivateEndpointItem struct {
 	ID            *string `json:"id,omitempty"`
 	CloudProvider *string `json:"cloud_provider,omitempty"`
 	ClusterID     *string `json:"cluster_id,omitempty"`
-	Region        *string `json:"region,omitempty"`
+	ClusterName   *string `json:"cluster_name,omitempty"`
+	RegionName    *string `json:"region_name,omitempty"`
 	EndpointName  *string `json:"endpoint_name,omitempty"`
 	Status        *string `json:"status,omitempty"`
 	Message       *string `json:"message,omitempty"`
@@ -289,20 +439,209 @@ type OpenapiCreatePrivateEndpointReq struct {
 }
 
 type OpenapiCreatePrivateEndpointResp struct {
-	ID            *string `json:"id,omitempty"`
-	CloudProvider *string `json:"cloud_provider,omitempty"`
-	ClusterID     *string `json:"cluster_id,omitempty"`
-	Region        *string `json:"region,omitempty"`
-	EndpointName  *string `json:"endpoint_name,omitempty"`
-	Status        *string `json:"status,omitempty"`
-	Message       *string `json:"message,omitempty"`
-	ServiceName   *string `json:"service_name,omitempty"`
-	ServiceStatus *string `json:"service_status,omitempty"`
+	PrivateEndpoint *OpenapiPrivateEndpointItem `json:"private_endpoint,omitempty"`
+}
+
+// Import API models
+type ImportSourceImportSourceType string
+
+const (
+	ImportSourceImportSourceTypeS3        ImportSourceImportSourceType = "S3"
+	ImportSourceImportSourceTypeGCS       ImportSourceImportSourceType = "GCS"
+	ImportSourceImportSourceTypeLOCALFILE ImportSourceImportSourceType = "LOCAL_FILE"
+)
+
+type ImportSourceFormatImportSourceFormatType string
+
+const (
+	ImportSourceFormatImportSourceFormatTypeCSV            ImportSourceFormatImportSourceFormatType = "CSV"
+	ImportSourceFormatImportSourceFormatTypePARQUET        ImportSourceFormatImportSourceFormatType = "PARQUET"
+	ImportSourceFormatImportSourceFormatTypeSQL            ImportSourceFormatImportSourceFormatType = "SQL"
+	ImportSourceFormatImportSourceFormatTypeAURORASNAPSHOT ImportSourceFormatImportSourceFormatType = "AURORA_SNAPSHOT"
+)
+
+type ImportStatusImportTaskPhase string
+
+const (
+	ImportStatusImportTaskPhasePREPARING ImportStatusImportTaskPhase = "PREPARING"
+	ImportStatusImportTaskPhaseIMPORTING ImportStatusImportTaskPhase = "IMPORTING"
+	ImportStatusImportTaskPhaseCOMPLETED ImportStatusImportTaskPhase = "COMPLETED"
+	ImportStatusImportTaskPhaseFAILED    ImportStatusImportTaskPhase = "FAILED"
+	ImportStatusImportTaskPhaseCANCELING ImportStatusImportTaskPhase = "CANCELING"
+	ImportStatusImportTaskPhaseCANCELED  ImportStatusImportTaskPhase = "CANCELED"
+)
+
+type UpdateImportTaskReqImportTaskAction string
+
+const (
+	UpdateImportTaskReqImportTaskActionCANCEL UpdateImportTaskReqImportTaskAction = "CANCEL"
+)
+
+type OpenapiListImportTasksResp struct {
+	Items []*OpenapiImportItem `json:"items,omitempty"`
+	Total *int64               `json:"total,omitempty"`
+}
+
+type OpenapiImportItem struct {
+	Metadata *OpenapiImportMetadata `json:"metadata,omitempty"`
+	Spec     *OpenapiImportSpec     `json:"spec,omitempty"`
+	Status   *OpenapiImportStatus   `json:"status,omitempty"`
+}
+
+type OpenapiImportMetadata struct {
+	ID              *string `json:"id,omitempty"`
+	Name            *string `json:"name,omitempty"`
+	CreateTimestamp *string `json:"create_timestamp,omitempty"`
+}
+
+type OpenapiImportSpec struct {
+	Source *OpenapiImportSource `json:"source,omitempty"`
+	Target *OpenapiImportTarget `json:"target,omitempty"`
+}
+
+type OpenapiImportSource struct {
+	Type                *ImportSourceImportSourceType `json:"type,omitempty"`
+	URI                 *string                       `json:"uri,omitempty"`
+	AwsAssumeRoleAccess *OpenapiAwsAssumeRoleAccess   `json:"aws_assume_role_access,omitempty"`
+	AwsKeyAccess        *OpenapiAwsKeyAccess          `json:"aws_key_access,omitempty"`
+	Format              *OpenapiImportSourceFormat    `json:"format,omitempty"`
+}
+
+type OpenapiAwsAssumeRoleAccess struct {
+	AssumeRole *string `json:"assume_role,omitempty"`
+}
+
+type OpenapiAwsKeyAccess struct {
+	AccessKeyID     *string `json:"access_key_id,omitempty"`
+	SecretAccessKey *string `json:"secret_access_key,omitempty"`
+}
+
+type OpenapiImportSourceFormat struct {
+	Type      *ImportSourceFormatImportSourceFormatType `json:"type,omitempty"`
+	CSVConfig *OpenapiImportSourceCSVConfig             `json:"csv_config,omitempty"`
+}
+
+type OpenapiImportSourceCSVConfig struct {
+	Delimiter       *string `json:"delimiter,omitempty"`
+	Quote           *string `json:"quote,omitempty"`
+	BackslashEscape *bool   `json:"backslash_escape,omitempty"`
+	HasHeaderRow    *bool   `json:"has_header_row,omitempty"`
+}
+
+type OpenapiImportTarget struct {
+	Tables []*OpenapiImportTargetTable `json:"tables,omitempty"`
+}
+
+type OpenapiImportTargetTable struct {
+	DatabaseName    *string `json:"database_name,omitempty"`
+	TableName       *string `json:"table_name,omitempty"`
+	FileNamePattern *string `json:"file_name_pattern,omitempty"`
+}
+
+type OpenapiImportStatus struct {
+	Phase                *ImportStatusImportTaskPhase `json:"phase,omitempty"`
+	ErrorMessage         *string                      `json:"error_message,omitempty"`
+	StartTimestamp       *string                      `json:"start_timestamp,omitempty"`
+	EndTimestamp         *string                      `json:"end_timestamp,omitempty"`
+	Progress             *OpenapiImportProgress       `json:"progress,omitempty"`
+	SourceTotalSizeBytes *string                      `json:"source_total_size_bytes,omitempty"`
+}
+
+type OpenapiImportProgress struct {
+	ImportProgress     *float64 `json:"import_progress,omitempty"`
+	ValidationProgress *float64 `json:"validation_progress,omitempty"`
+}
+
+type OpenapiCreateImportTaskReq struct {
+	Name    *string                         `json:"name,omitempty"`
+	Spec    *OpenapiImportSpec              `json:"spec,omitempty"`
+	Options *OpenapiCreateImportTaskOptions `json:"options,omitempty"`
+}
+
+type OpenapiCreateImportTaskOptions struct {
+	PreCreateTables []*OpenapiTableDefinition `json:"pre_create_tables,omitempty"`
+}
+
+type OpenapiTableDefinition struct {
+	DatabaseName *string             `json:"database_name,omitempty"`
+	TableName    *string             `json:"table_name,omitempty"`
+	Schema       *OpenapiTableSchema `json:"schema,omitempty"`
+}
+
+type OpenapiTableSchema struct {
+	ColumnDefinitions []*OpenapiColumnDefinition `json:"column_definitions,omitempty"`
+	PrimaryKeyColumns []string                   `json:"primary_key_columns,omitempty"`
+}
+
+type OpenapiColumnDefinition struct {
+	ColumnName *string `json:"column_name,omitempty"`
+	ColumnType *string `json:"column_type,omitempty"`
+}
+
+type OpenapiCreateImportTaskResp struct {
+	ID *string `json:"id,omitempty"`
+}
+
+type OpenapiUpdateImportTaskReq struct {
+	Action *UpdateImportTaskReqImportTaskAction `json:"action,omitempty"`
+}
+
+type OpenapiUploadLocalFileReq struct {
+	LocalFileName *string                  `json:"local_file_name,omitempty"`
+	Payload       *OpenapiLocalFilePayload `json:"payload,omitempty"`
+}
+
+type OpenapiLocalFilePayload struct {
+	TotalSizeBytes *string `json:"total_size_bytes,omitempty"`
+	Content        *string `json:"content,omitempty"`
+}
+
+type OpenapiUploadLocalFileResp struct {
+	UploadStubID *string `json:"upload_stub_id,omitempty"`
+}
+
+type OpenapiPreviewImportDataReq struct {
+	Spec           *OpenapiImportSpec `json:"spec,omitempty"`
+	LimitRowsCount *int64             `json:"limit_rows_count,omitempty"`
+}
+
+type OpenapiPreviewImportDataResp struct {
+	TablePreviews []*OpenapiTablePreview `json:"table_previews,omitempty"`
+}
+
+type OpenapiTablePreview struct {
+	DatabaseName  *string             `json:"database_name,omitempty"`
+	TableName     *string             `json:"table_name,omitempty"`
+	SchemaPreview *OpenapiTableSchema `json:"schema_preview,omitempty"`
+	DataPreview   *OpenapiTableData   `json:"data_preview,omitempty"`
+}
+
+type OpenapiTableData struct {
+	ColumnNames []string               `json:"column_names,omitempty"`
+	Rows        []*OpenapiTableDataRow `json:"rows,omitempty"`
+}
+
+type OpenapiTableDataRow struct {
+	Columns []string `json:"columns,omitempty"`
+}
+
+type OpenapiImportTaskRoleInfo struct {
+	AwsImportRole *OpenapiAwsImportTaskRoleInfo `json:"aws_import_role,omitempty"`
+	GcpImportRole *OpenapiGcpImportTaskRoleInfo `json:"gcp_import_role,omitempty"`
+}
+
+type OpenapiAwsImportTaskRoleInfo struct {
+	AccountID  *string `json:"account_id,omitempty"`
+	ExternalID *string `json:"external_id,omitempty"`
+}
+
+type OpenapiGcpImportTaskRoleInfo struct {
+	AccountID *string `json:"account_id,omitempty"`
 }
 
-// ErrorResponse represents an error response from the API
+// ErrorResponse represents an error response from the API (googlerpcStatus)
 type ErrorResponse struct {
-	Code    *int64        `json:"code,omitempty"`
-	Message *string       `json:"message,omitempty"`
-	Details []interface{} `json:"details,omitempty"`
+	Code    *int64         `json:"code,omitempty"`
+	Message *string        `json:"message,omitempty"`
+	Details []*ProtobufAny `json:"details,omitempty"`
 }
@@ -0,0 +1,149 @@
+// Package ratelimit provides a client-side token-bucket limiter that keeps
+// requests under the TiDB Cloud API quota of 100 requests per minute per API
+// key, instead of exceeding it and falling back to retries.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/5st7/tidb-cloud-go/pkg/errors"
+)
+
+const (
+	// DefaultLimit is the number of requests TiDB Cloud allows per API key per minute
+	DefaultLimit = 100
+	// DefaultPeriod is the window DefaultLimit applies to
+	DefaultPeriod = time.Minute
+)
+
+// Limiter is a token bucket holding up to limit tokens, refilled at limit per
+// period. Each request takes a token, waiting for one if the bucket is empty.
+// The limiter adapts to the rate-limit headers of responses (see Observe), so
+// requests made with the same key by other processes are accounted for.
+// A Limiter is safe for concurrent use.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64 // Tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time // Time of the last refill; in the future while the server blocks requests
+}
+
+// NewLimiter creates a limiter allowing limit requests per period, starting
+// with a full bucket.
+func NewLimiter(limit int, period time.Duration) *Limiter {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if period <= 0 {
+		period = DefaultPeriod
+	}
+	return &Limiter{
+		rate:   float64(limit) / period.Seconds(),
+		burst:  float64(limit),
+		tokens: float64(limit),
+		last:   time.Now(),
+	}
+}
+
+// NewDefaultLimiter creates a limiter matching the TiDB Cloud quota of
+// DefaultLimit requests per DefaultPeriod.
+func NewDefaultLimiter() *Limiter {
+	return NewLimiter(DefaultLimit, DefaultPeriod)
+}
+
+var shared = struct {
+	sync.Mutex
+	limiters map[string]*Limiter
+}{limiters: make(map[string]*Limiter)}
+
+// ForKey returns the default limiter shared by every client in the process
+// that uses the API key with the given public key.
+func ForKey(publicKey string) *Limiter {
+	shared.Lock()
+	defer shared.Unlock()
+
+	l, ok := shared.limiters[publicKey]
+	if !ok {
+		l = NewDefaultLimiter()
+		shared.limiters[publicKey] = l
+	}
+	return l
+}
+
+// Wait takes a token, blocking until one is available or ctx is done.
+func (l *Limiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.refill(now)
+	l.tokens--
+
+	var delay time.Duration
+	if l.last.After(now) {
+		delay = l.last.Sub(now)
+	}
+	if l.tokens < 0 {
+		delay += time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		// Give the reserved token back to later requests
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Observe adapts the limiter to the rate-limit headers of a response, as
+// parsed by errors.ParseRateLimit; a nil rl is ignored. The reported limit
+// per minute replaces the configured rate, fewer remaining requests than
+// tokens empty the bucket accordingly, and no requests remaining pauses the
+// limiter until the limit resets.
+func (l *Limiter) Observe(rl *errors.RateLimit) {
+	if rl == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.refill(now)
+
+	if rl.Limit > 0 && float64(rl.Limit) != l.burst {
+		l.burst = float64(rl.Limit)
+		l.rate = l.burst / time.Minute.Seconds()
+		l.tokens = min(l.tokens, l.burst)
+	}
+
+	if rl.Remaining >= 0 && float64(rl.Remaining) < l.tokens {
+		l.tokens = float64(rl.Remaining)
+	}
+
+	if rl.Remaining == 0 && rl.Reset.After(l.last) {
+		l.last = rl.Reset
+	}
+}
+
+// refill adds the tokens accrued since the last refill.
+func (l *Limiter) refill(now time.Time) {
+	if !now.After(l.last) {
+		return
+	}
+	l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*l.rate, l.burst)
+	l.last = now
+}
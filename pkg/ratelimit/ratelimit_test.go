@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/5st7/tidb-cloud-go/pkg/errors"
+)
+
+func TestLimiter_Wait(t *testing.T) {
+	// 5 requests per 100ms: a burst of 5, then one every 20ms
+	l := NewLimiter(5, 100*time.Millisecond)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("Expected the burst to pass without waiting, took %v", elapsed)
+	}
+
+	start = time.Now()
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("Expected to wait for a token once the bucket is empty, took %v", elapsed)
+	}
+}
+
+func TestLimiter_WaitCancelled(t *testing.T) {
+	l := NewLimiter(1, time.Hour)
+	l.Wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait() error = %v, want context.DeadlineExceeded", err)
+	}
+	if l.tokens < -0.01 {
+		t.Errorf("Expected the reserved token to be returned, tokens = %v", l.tokens)
+	}
+}
+
+func TestLimiter_Observe(t *testing.T) {
+	t.Run("remaining lowers tokens", func(t *testing.T) {
+		l := NewDefaultLimiter()
+		l.Observe(&errors.RateLimit{Limit: 100, Remaining: 3})
+		if l.tokens > 3.01 {
+			t.Errorf("Expected at most 3 tokens, got %v", l.tokens)
+		}
+	})
+
+	t.Run("limit replaces rate", func(t *testing.T) {
+		l := NewDefaultLimiter()
+		l.Observe(&errors.RateLimit{Limit: 300, Remaining: -1})
+		if l.burst != 300 || l.rate != 5 {
+			t.Errorf("Expected burst 300 and rate 5/s, got %v and %v", l.burst, l.rate)
+		}
+	})
+
+	t.Run("exhausted quota pauses until reset", func(t *testing.T) {
+		l := NewLimiter(1000, time.Second)
+		l.Observe(&errors.RateLimit{Limit: -1, Remaining: 0, Reset: time.Now().Add(50 * time.Millisecond)})
+
+		start := time.Now()
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() unexpected error: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+			t.Errorf("Expected to wait for the reset, took %v", elapsed)
+		}
+	})
+
+	t.Run("nil is ignored", func(t *testing.T) {
+		l := NewDefaultLimiter()
+		l.Observe(nil)
+		if l.tokens != DefaultLimit {
+			t.Errorf("Expected a full bucket, got %v", l.tokens)
+		}
+	})
+}
+
+func TestForKey(t *testing.T) {
+	if ForKey("key-a") != ForKey("key-a") {
+		t.Error("Expected the same limiter for the same key")
+	}
+	if ForKey("key-a") == ForKey("key-b") {
+		t.Error("Expected different limiters for different keys")
+	}
+}
@@ -0,0 +1,52 @@
+package retry
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff computes the delay before the given retry attempt, starting at 1,
+// from the policy's base and maximum delay and the delay before the previous
+// attempt, which is zero before the first retry.
+type Backoff func(attempt int, baseDelay, maxDelay, previous time.Duration) time.Duration
+
+// ExponentialBackoff starts at baseDelay and doubles the delay with each
+// attempt, capped at maxDelay. It is the default Backoff.
+func ExponentialBackoff(attempt int, baseDelay, maxDelay, previous time.Duration) time.Duration {
+	// Compare as floats so that large attempts do not overflow
+	delay := math.Pow(2, float64(attempt-1)) * float64(baseDelay)
+	if delay > float64(maxDelay) {
+		return maxDelay
+	}
+	return time.Duration(delay)
+}
+
+// FullJitterBackoff waits a random delay between zero and the
+// ExponentialBackoff delay, so that clients failing together do not retry in
+// lockstep.
+func FullJitterBackoff(attempt int, baseDelay, maxDelay, previous time.Duration) time.Duration {
+	return randomDelay(0, ExponentialBackoff(attempt, baseDelay, maxDelay, previous))
+}
+
+// DecorrelatedJitterBackoff waits a random delay between baseDelay and three
+// times the previous delay, capped at maxDelay.
+func DecorrelatedJitterBackoff(attempt int, baseDelay, maxDelay, previous time.Duration) time.Duration {
+	if previous < baseDelay {
+		previous = baseDelay
+	}
+	return min(randomDelay(baseDelay, 3*previous), maxDelay)
+}
+
+// ConstantBackoff waits baseDelay before every retry.
+func ConstantBackoff(attempt int, baseDelay, maxDelay, previous time.Duration) time.Duration {
+	return baseDelay
+}
+
+// randomDelay returns a random delay in [lo, hi].
+func randomDelay(lo, hi time.Duration) time.Duration {
+	if hi <= lo {
+		return lo
+	}
+	return lo + rand.N(hi-lo+1)
+}
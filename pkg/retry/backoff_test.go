@@ -0,0 +1,64 @@
+package retry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	base := 100 * time.Millisecond
+	maxDelay := 2 * time.Second
+
+	tests := []struct {
+		name        string
+		backoff     Backoff
+		attempt     int
+		previous    time.Duration
+		minExpected time.Duration
+		maxExpected time.Duration
+	}{
+		{name: "exponential first retry", backoff: ExponentialBackoff, attempt: 1, minExpected: base, maxExpected: base},
+		{name: "exponential third retry", backoff: ExponentialBackoff, attempt: 3, minExpected: 400 * time.Millisecond, maxExpected: 400 * time.Millisecond},
+		{name: "exponential capped", backoff: ExponentialBackoff, attempt: 10, minExpected: maxDelay, maxExpected: maxDelay},
+		{name: "exponential overflow capped", backoff: ExponentialBackoff, attempt: 100, minExpected: maxDelay, maxExpected: maxDelay},
+		{name: "full jitter", backoff: FullJitterBackoff, attempt: 3, minExpected: 0, maxExpected: 400 * time.Millisecond},
+		{name: "full jitter capped", backoff: FullJitterBackoff, attempt: 10, minExpected: 0, maxExpected: maxDelay},
+		{name: "decorrelated jitter first retry", backoff: DecorrelatedJitterBackoff, attempt: 1, minExpected: base, maxExpected: 300 * time.Millisecond},
+		{name: "decorrelated jitter grows from previous", backoff: DecorrelatedJitterBackoff, attempt: 2, previous: 500 * time.Millisecond, minExpected: base, maxExpected: 1500 * time.Millisecond},
+		{name: "decorrelated jitter capped", backoff: DecorrelatedJitterBackoff, attempt: 5, previous: time.Minute, minExpected: base, maxExpected: maxDelay},
+		{name: "constant", backoff: ConstantBackoff, attempt: 5, previous: time.Second, minExpected: base, maxExpected: base},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				delay := tt.backoff(tt.attempt, base, maxDelay, tt.previous)
+				if delay < tt.minExpected || delay > tt.maxExpected {
+					t.Fatalf("Expected delay in [%v, %v], got %v", tt.minExpected, tt.maxExpected, delay)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoff_JitterSpreadsDelays(t *testing.T) {
+	for name, backoff := range map[string]Backoff{"full": FullJitterBackoff, "decorrelated": DecorrelatedJitterBackoff} {
+		seen := make(map[time.Duration]bool)
+		for i := 0; i < 20; i++ {
+			seen[backoff(3, time.Second, time.Minute, 2*time.Second)] = true
+		}
+		if len(seen) < 2 {
+			t.Errorf("%s jitter returned the same delay every time", name)
+		}
+	}
+}
+
+func TestRetryPolicy_CustomBackoff(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 3, BaseDelay: 250 * time.Millisecond, MaxDelay: time.Second, Backoff: ConstantBackoff}
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		if delay := policy.CalculateDelay(attempt); delay != 250*time.Millisecond {
+			t.Errorf("CalculateDelay(%d) = %v, want 250ms", attempt, delay)
+		}
+	}
+}
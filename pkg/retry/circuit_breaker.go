@@ -0,0 +1,214 @@
+package retry
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/5st7/tidb-cloud-go/pkg/errors"
+)
+
+// ErrCircuitOpen is matched by errors.Is for the CircuitOpenError returned
+// while a circuit breaker rejects requests.
+var ErrCircuitOpen = stderrors.New("circuit breaker is open")
+
+// CircuitOpenError is returned without calling the operation while a
+// CircuitBreaker is open, or half-open with all trial requests in flight.
+type CircuitOpenError struct {
+	// Until is when the breaker lets a trial request through again
+	Until time.Time
+}
+
+// Error implements the error interface.
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrCircuitOpen, e.Until.Format(time.RFC3339))
+}
+
+// Is reports whether target is ErrCircuitOpen.
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through while counting failures
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects every request until OpenTimeout has passed
+	CircuitOpen
+	// CircuitHalfOpen lets HalfOpenRequests trial requests through to probe
+	// whether the API has recovered
+	CircuitHalfOpen
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreakerPolicy configures when a CircuitBreaker opens and recovers.
+type CircuitBreakerPolicy struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before letting trial requests through
+	OpenTimeout time.Duration
+	// HalfOpenRequests is the number of trial requests that must succeed to close the breaker
+	HalfOpenRequests int
+}
+
+// NewCircuitBreakerPolicy creates a circuit breaker policy with default values.
+// Default configuration:
+//   - FailureThreshold: 5 consecutive failures
+//   - OpenTimeout: 30 seconds
+//   - HalfOpenRequests: 1 trial request
+func NewCircuitBreakerPolicy() *CircuitBreakerPolicy {
+	return &CircuitBreakerPolicy{
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+		HalfOpenRequests: 1,
+	}
+}
+
+// CircuitBreaker stops requests to a degraded API instead of letting every
+// caller retry against it. Failures are retryable API errors (see
+// APIError.IsRetryable) and network errors; other errors, such as a 404, show
+// that the API is answering and count as successes, as do validation errors,
+// which are never sent. A CircuitBreaker can be shared by several executors
+// and clients, and is safe for concurrent use.
+type CircuitBreaker struct {
+	policy *CircuitBreakerPolicy
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int       // Consecutive failures while closed
+	openedAt  time.Time // When the breaker last opened
+	inFlight  int       // Trial requests in flight while half-open
+	successes int       // Successful trial requests while half-open
+}
+
+// NewCircuitBreaker creates a closed circuit breaker with the given policy,
+// or the default policy if it is nil. A FailureThreshold that is not positive
+// is replaced by the default.
+func NewCircuitBreaker(policy *CircuitBreakerPolicy) *CircuitBreaker {
+	defaults := NewCircuitBreakerPolicy()
+	if policy == nil {
+		policy = defaults
+	}
+	p := *policy
+	if p.FailureThreshold <= 0 {
+		p.FailureThreshold = defaults.FailureThreshold
+	}
+	return &CircuitBreaker{policy: &p}
+}
+
+// State returns the current state, e.g. for a health check. An open breaker
+// whose OpenTimeout has passed reports CircuitHalfOpen.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance(time.Now())
+	return b.state
+}
+
+// Allow reports whether a request may be sent, returning a *CircuitOpenError
+// if not. Every allowed request must be followed by a call to Record.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.advance(now)
+
+	switch b.state {
+	case CircuitOpen:
+		return &CircuitOpenError{Until: b.openedAt.Add(b.policy.OpenTimeout)}
+	case CircuitHalfOpen:
+		if b.inFlight >= b.halfOpenRequests() {
+			return &CircuitOpenError{Until: now}
+		}
+		b.inFlight++
+	}
+	return nil
+}
+
+// Record reports the outcome of a request allowed by Allow. Cancellation by
+// the caller's context is not a failure of the API and is ignored.
+func (b *CircuitBreaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitHalfOpen && b.inFlight > 0 {
+		b.inFlight--
+	}
+	if stderrors.Is(err, context.Canceled) || stderrors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+
+	if !isFailure(err) {
+		switch b.state {
+		case CircuitClosed:
+			b.failures = 0
+		case CircuitHalfOpen:
+			b.successes++
+			if b.successes >= b.halfOpenRequests() {
+				b.state = CircuitClosed
+				b.failures = 0
+			}
+		}
+		return
+	}
+
+	switch b.state {
+	case CircuitClosed:
+		b.failures++
+		if b.failures >= b.policy.FailureThreshold {
+			b.open(time.Now())
+		}
+	case CircuitHalfOpen:
+		b.open(time.Now())
+	}
+}
+
+// advance moves an open breaker to half-open once OpenTimeout has passed.
+func (b *CircuitBreaker) advance(now time.Time) {
+	if b.state == CircuitOpen && !now.Before(b.openedAt.Add(b.policy.OpenTimeout)) {
+		b.state = CircuitHalfOpen
+		b.inFlight = 0
+		b.successes = 0
+	}
+}
+
+func (b *CircuitBreaker) open(now time.Time) {
+	b.state = CircuitOpen
+	b.openedAt = now
+	b.failures = 0
+}
+
+func (b *CircuitBreaker) halfOpenRequests() int {
+	return max(b.policy.HalfOpenRequests, 1)
+}
+
+// isFailure reports whether err shows that the API is degraded.
+func isFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr errors.APIError
+	if stderrors.As(err, &apiErr) {
+		return apiErr.IsRetryable()
+	}
+	var validationErr *errors.ValidationError
+	return !stderrors.As(err, &validationErr)
+}
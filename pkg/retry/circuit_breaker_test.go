@@ -0,0 +1,144 @@
+package retry
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/5st7/tidb-cloud-go/pkg/errors"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	breaker := NewCircuitBreaker(&CircuitBreakerPolicy{FailureThreshold: 3, OpenTimeout: 50 * time.Millisecond, HalfOpenRequests: 2})
+
+	call := func(err error) error {
+		if allowErr := breaker.Allow(); allowErr != nil {
+			return allowErr
+		}
+		breaker.Record(err)
+		return nil
+	}
+
+	// Non-retryable errors and cancellation do not count as failures
+	call(errors.APIError{StatusCode: 500})
+	call(errors.APIError{StatusCode: 502})
+	call(errors.APIError{StatusCode: 404})
+	call(context.Canceled)
+	call(fmt.Errorf("network error"))
+	if state := breaker.State(); state != CircuitClosed {
+		t.Fatalf("Expected closed after a success reset the count, got %v", state)
+	}
+
+	call(errors.APIError{StatusCode: 503})
+	call(errors.APIError{StatusCode: 504})
+	if state := breaker.State(); state != CircuitOpen {
+		t.Fatalf("Expected open after 3 consecutive failures, got %v", state)
+	}
+
+	err := call(nil)
+	var openErr *CircuitOpenError
+	if !stderrors.As(err, &openErr) || !stderrors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected CircuitOpenError, got %v", err)
+	}
+	if openErr.Until.Before(time.Now()) {
+		t.Errorf("Expected Until in the future, got %v", openErr.Until)
+	}
+
+	// After the timeout a limited number of trial requests are let through
+	time.Sleep(60 * time.Millisecond)
+	if state := breaker.State(); state != CircuitHalfOpen {
+		t.Fatalf("Expected half-open after the timeout, got %v", state)
+	}
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Allow() first trial unexpected error: %v", err)
+	}
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("Allow() second trial unexpected error: %v", err)
+	}
+	if err := breaker.Allow(); !stderrors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Allow() third trial error = %v, want ErrCircuitOpen", err)
+	}
+
+	// A failed trial reopens the breaker
+	breaker.Record(nil)
+	breaker.Record(errors.APIError{StatusCode: 503})
+	if state := breaker.State(); state != CircuitOpen {
+		t.Fatalf("Expected open after a failed trial, got %v", state)
+	}
+
+	// Successful trials close it
+	time.Sleep(60 * time.Millisecond)
+	call(nil)
+	call(nil)
+	if state := breaker.State(); state != CircuitClosed {
+		t.Errorf("Expected closed after successful trials, got %v", state)
+	}
+}
+
+func TestNewCircuitBreaker_Defaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *CircuitBreakerPolicy
+	}{
+		{name: "nil policy", policy: nil},
+		{name: "zero failure threshold", policy: &CircuitBreakerPolicy{OpenTimeout: 30 * time.Second}},
+		{name: "negative failure threshold", policy: &CircuitBreakerPolicy{FailureThreshold: -1, OpenTimeout: 30 * time.Second}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaker := NewCircuitBreaker(tt.policy)
+			threshold := NewCircuitBreakerPolicy().FailureThreshold
+
+			for i := 0; i < threshold; i++ {
+				if state := breaker.State(); state != CircuitClosed {
+					t.Fatalf("Expected closed after %d failures, got %v", i, state)
+				}
+				if err := breaker.Allow(); err != nil {
+					t.Fatalf("Allow() unexpected error: %v", err)
+				}
+				breaker.Record(errors.APIError{StatusCode: 503})
+			}
+			if state := breaker.State(); state != CircuitOpen {
+				t.Errorf("Expected open after %d failures, got %v", threshold, state)
+			}
+		})
+	}
+}
+
+func TestCircuitState_String(t *testing.T) {
+	for state, expected := range map[CircuitState]string{CircuitClosed: "closed", CircuitOpen: "open", CircuitHalfOpen: "half-open", CircuitState(7): "CircuitState(7)"} {
+		if result := state.String(); result != expected {
+			t.Errorf("Expected %q, got %q", expected, result)
+		}
+	}
+}
+
+func TestRetryExecutor_CircuitBreaker(t *testing.T) {
+	breaker := NewCircuitBreaker(&CircuitBreakerPolicy{FailureThreshold: 2, OpenTimeout: time.Minute})
+	executor := NewRetryExecutor(&RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}).WithCircuitBreaker(breaker)
+
+	callCount := 0
+	err := executor.Execute(context.Background(), func() error {
+		callCount++
+		return errors.APIError{StatusCode: 503}
+	})
+	if !stderrors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if callCount != 2 {
+		t.Errorf("Expected retries to stop once the breaker opened after 2 calls, got %d", callCount)
+	}
+
+	// Other callers fail fast without calling the operation
+	callCount = 0
+	err = executor.Execute(context.Background(), func() error {
+		callCount++
+		return nil
+	})
+	if !stderrors.Is(err, ErrCircuitOpen) || callCount != 0 {
+		t.Errorf("Expected fail-fast ErrCircuitOpen with 0 calls, got %v and %d calls", err, callCount)
+	}
+}
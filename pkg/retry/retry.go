@@ -5,19 +5,33 @@ package retry
 
 import (
 	"context"
-	"math"
+	stderrors "errors"
 	"time"
 
 	"github.com/5st7/tidb-cloud-go/pkg/errors"
 )
 
+// DefaultMaxServerDelay is the longest server-advertised delay a RetryPolicy
+// waits for when MaxServerDelay is zero. It covers the one-minute window of
+// the TiDB Cloud rate limit.
+const DefaultMaxServerDelay = time.Minute
+
 // RetryPolicy defines the retry policy for API requests.
 // It configures the maximum number of attempts, base delay, and maximum delay
-// for exponential backoff retry logic.
+// for exponential backoff retry logic. Set Backoff to spread out retries of
+// many clients with jitter, or to retry at a constant interval.
+//
+// When a retryable API error advertises when to retry, through the Retry-After
+// or X-Ratelimit-Reset header, the policy waits that long instead of backing
+// off, provided the delay is at most MaxServerDelay. A MaxServerDelay of zero
+// uses DefaultMaxServerDelay; a negative value ignores server-advertised delays.
 type RetryPolicy struct {
-	MaxAttempts int
-	BaseDelay   time.Duration
-	MaxDelay    time.Duration
+	MaxAttempts    int
+	BaseDelay      time.Duration
+	MaxDelay       time.Duration
+	MaxServerDelay time.Duration
+	// Backoff computes the delay between retries; nil uses ExponentialBackoff
+	Backoff Backoff
 }
 
 // NewRetryPolicy creates a new retry policy with default values.
@@ -25,46 +39,113 @@ type RetryPolicy struct {
 //   - MaxAttempts: 3 (initial attempt + 2 retries)
 //   - BaseDelay: 1 second
 //   - MaxDelay: 30 seconds
+//   - MaxServerDelay: 1 minute
 func NewRetryPolicy() *RetryPolicy {
 	return &RetryPolicy{
-		MaxAttempts: 3,
-		BaseDelay:   1 * time.Second,
-		MaxDelay:    30 * time.Second,
+		MaxAttempts:    3,
+		BaseDelay:      1 * time.Second,
+		MaxDelay:       30 * time.Second,
+		MaxServerDelay: DefaultMaxServerDelay,
 	}
 }
 
 // ShouldRetry determines if an error should be retried based on the error type
 // and current attempt count. It returns true for retryable errors like rate limits
-// and server errors, but false for client errors like authentication failures.
+// and server errors, but false for client errors like authentication failures
+// and validation errors. Errors are classified however they are wrapped, and a
+// PermanentError is never retried.
 func (p *RetryPolicy) ShouldRetry(err error, attempt int) bool {
 	if attempt >= p.MaxAttempts {
 		return false
 	}
 
-	// Check if it's an API error
-	if apiErr, ok := err.(errors.APIError); ok {
+	var permanent *PermanentError
+	if stderrors.As(err, &permanent) {
+		return false
+	}
+
+	// Check if it's an API error, however it is wrapped
+	var apiErr errors.APIError
+	if stderrors.As(err, &apiErr) {
 		return apiErr.IsRetryable()
 	}
 
+	// Requests rejected before they were sent fail the same way every time
+	var validationErr *errors.ValidationError
+	if stderrors.As(err, &validationErr) {
+		return false
+	}
+
 	// Retry non-API errors (network errors, etc.)
 	return true
 }
 
-// CalculateDelay calculates the delay for the given attempt using exponential backoff.
-// The delay starts at BaseDelay and doubles with each attempt, capped at MaxDelay.
+// CalculateDelay calculates the delay for the given attempt using the policy's
+// Backoff. By default the delay starts at BaseDelay and doubles with each
+// attempt, capped at MaxDelay.
 // Formula: min(BaseDelay * 2^(attempt-1), MaxDelay)
 func (p *RetryPolicy) CalculateDelay(attempt int) time.Duration {
-	delay := time.Duration(math.Pow(2, float64(attempt-1))) * p.BaseDelay
-	if delay > p.MaxDelay {
-		delay = p.MaxDelay
+	return p.backoffDelay(attempt, 0)
+}
+
+// backoffDelay applies the policy's Backoff, given the previous delay.
+func (p *RetryPolicy) backoffDelay(attempt int, previous time.Duration) time.Duration {
+	backoff := p.Backoff
+	if backoff == nil {
+		backoff = ExponentialBackoff
 	}
-	return delay
+	return backoff(attempt, p.BaseDelay, p.MaxDelay, previous)
+}
+
+// RetryDelay returns the delay before retrying after err on the given attempt:
+// the server-advertised delay of an API error if it is within MaxServerDelay,
+// and CalculateDelay(attempt) otherwise.
+func (p *RetryPolicy) RetryDelay(err error, attempt int) time.Duration {
+	return p.retryDelay(err, attempt, 0)
+}
+
+// retryDelay is RetryDelay given the delay before the previous attempt, which
+// DecorrelatedJitterBackoff builds on.
+func (p *RetryPolicy) retryDelay(err error, attempt int, previous time.Duration) time.Duration {
+	var apiErr errors.APIError
+	if stderrors.As(err, &apiErr) && p.MaxServerDelay >= 0 {
+		maxServerDelay := p.MaxServerDelay
+		if maxServerDelay == 0 {
+			maxServerDelay = DefaultMaxServerDelay
+		}
+		if delay, ok := apiErr.RetryDelay(); ok && delay <= maxServerDelay {
+			return delay
+		}
+	}
+	return p.backoffDelay(attempt, previous)
+}
+
+// PermanentError wraps an error that must not be retried whatever the policy,
+// e.g. a failed request that the server may already have acted on.
+type PermanentError struct {
+	Err error
+}
+
+// Permanent wraps err so that RetryExecutor returns it without retrying.
+func Permanent(err error) error {
+	return &PermanentError{Err: err}
+}
+
+// Error implements the error interface.
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *PermanentError) Unwrap() error {
+	return e.Err
 }
 
 // RetryExecutor executes operations with retry logic.
 // It applies the configured retry policy and handles context cancellation.
 type RetryExecutor struct {
-	policy *RetryPolicy
+	policy  *RetryPolicy
+	breaker *CircuitBreaker
 }
 
 // NewRetryExecutor creates a new retry executor with the specified policy.
@@ -75,15 +156,40 @@ func NewRetryExecutor(policy *RetryPolicy) *RetryExecutor {
 	}
 }
 
+// WithCircuitBreaker returns a copy of the executor that sends every attempt
+// through breaker. While the breaker is open, Execute fails fast with a
+// *CircuitOpenError instead of calling the operation or retrying.
+func (e *RetryExecutor) WithCircuitBreaker(breaker *CircuitBreaker) *RetryExecutor {
+	return &RetryExecutor{
+		policy:  e.policy,
+		breaker: breaker,
+	}
+}
+
 // Execute executes an operation with retry logic according to the configured policy.
-// It respects context cancellation and applies exponential backoff between retries.
+// It respects context cancellation and applies exponential backoff between retries,
+// or waits for the delay advertised by the server (see RetryPolicy).
 // The operation function is called repeatedly until it succeeds, fails with a
 // non-retryable error, or the maximum attempts are reached.
 func (e *RetryExecutor) Execute(ctx context.Context, operation func() error) error {
 	var lastErr error
+	var delay time.Duration
 
 	for attempt := 0; attempt <= e.policy.MaxAttempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if e.breaker != nil {
+			if err := e.breaker.Allow(); err != nil {
+				return err
+			}
+		}
+
 		err := operation()
+		if e.breaker != nil {
+			e.breaker.Record(err)
+		}
 		if err == nil {
 			return nil
 		}
@@ -96,7 +202,7 @@ func (e *RetryExecutor) Execute(ctx context.Context, operation func() error) err
 		}
 
 		// Calculate and wait for delay
-		delay := e.policy.CalculateDelay(attempt + 1)
+		delay = e.policy.retryDelay(err, attempt+1, delay)
 
 		select {
 		case <-ctx.Done():
@@ -48,6 +48,36 @@ func TestRetryPolicy_ShouldRetry(t *testing.T) {
 			attempt:  1,
 			expected: true,
 		},
+		{
+			name:     "permanent error",
+			err:      Permanent(errors.APIError{StatusCode: 502}),
+			attempt:  1,
+			expected: false,
+		},
+		{
+			name:     "wrapped bad request error",
+			err:      fmt.Errorf("failed to execute request: %w", errors.APIError{StatusCode: 400}),
+			attempt:  1,
+			expected: false,
+		},
+		{
+			name:     "wrapped server error",
+			err:      fmt.Errorf("failed to execute request: %w", errors.APIError{StatusCode: 503}),
+			attempt:  1,
+			expected: true,
+		},
+		{
+			name:     "wrapped permanent error",
+			err:      fmt.Errorf("attempt 2: %w", Permanent(fmt.Errorf("network error"))),
+			attempt:  1,
+			expected: false,
+		},
+		{
+			name:     "validation error",
+			err:      errors.Required("project ID"),
+			attempt:  1,
+			expected: false,
+		},
 		{
 			name:     "non-API error at max attempts",
 			err:      fmt.Errorf("network error"),
@@ -101,6 +131,65 @@ func TestRetryPolicy_CalculateDelay(t *testing.T) {
 	}
 }
 
+func TestRetryPolicy_RetryDelay(t *testing.T) {
+	rateLimited := func(retryAfter time.Duration) error {
+		return errors.APIError{StatusCode: 429, Code: 49900007, RateLimit: &errors.RateLimit{RetryAfter: retryAfter}}
+	}
+
+	tests := []struct {
+		name           string
+		maxServerDelay time.Duration
+		err            error
+		expected       time.Duration
+	}{
+		{
+			name:           "server delay within maximum",
+			maxServerDelay: 30 * time.Second,
+			err:            rateLimited(20 * time.Second),
+			expected:       20 * time.Second,
+		},
+		{
+			name:           "server delay beyond maximum falls back to backoff",
+			maxServerDelay: 30 * time.Second,
+			err:            rateLimited(45 * time.Second),
+			expected:       2 * time.Second,
+		},
+		{
+			name:           "zero maximum uses default",
+			maxServerDelay: 0,
+			err:            rateLimited(45 * time.Second),
+			expected:       45 * time.Second,
+		},
+		{
+			name:           "negative maximum ignores server delay",
+			maxServerDelay: -1,
+			err:            rateLimited(5 * time.Second),
+			expected:       2 * time.Second,
+		},
+		{
+			name:     "no server delay",
+			err:      errors.APIError{StatusCode: 503},
+			expected: 2 * time.Second,
+		},
+		{
+			name:     "non-API error",
+			err:      fmt.Errorf("network error"),
+			expected: 2 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := NewRetryPolicy()
+			policy.MaxServerDelay = tt.maxServerDelay
+
+			if result := policy.RetryDelay(tt.err, 2); result != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
 func TestRetryExecutor_Execute(t *testing.T) {
 	executor := NewRetryExecutor(NewRetryPolicy())
 
@@ -170,4 +259,68 @@ func TestRetryExecutor_Execute(t *testing.T) {
 			t.Errorf("Expected 1 call, got %d", callCount)
 		}
 	})
+
+	t.Run("cancelled context skips operation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		callCount := 0
+		operation := func() error {
+			callCount++
+			return nil
+		}
+
+		err := executor.Execute(ctx, operation)
+		if err != context.Canceled {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+		if callCount != 0 {
+			t.Errorf("Expected 0 calls, got %d", callCount)
+		}
+	})
+
+	t.Run("waits for server-advertised delay", func(t *testing.T) {
+		callCount := 0
+		operation := func() error {
+			callCount++
+			if callCount == 1 {
+				return errors.APIError{StatusCode: 429, Code: 49900007, RateLimit: &errors.RateLimit{RetryAfter: 50 * time.Millisecond}}
+			}
+			return nil
+		}
+
+		start := time.Now()
+		if err := executor.Execute(context.Background(), operation); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed > 500*time.Millisecond {
+			t.Errorf("Expected to wait for Retry-After instead of backing off, took %v", elapsed)
+		}
+		if callCount != 2 {
+			t.Errorf("Expected 2 calls, got %d", callCount)
+		}
+	})
+
+	t.Run("deadline interrupts backoff", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		callCount := 0
+		operation := func() error {
+			callCount++
+			return errors.APIError{StatusCode: 500}
+		}
+
+		start := time.Now()
+		err := executor.Execute(ctx, operation)
+		if err != context.DeadlineExceeded {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("Expected backoff to stop at the deadline, took %v", elapsed)
+		}
+		if callCount != 1 {
+			t.Errorf("Expected 1 call, got %d", callCount)
+		}
+	})
 }